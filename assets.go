@@ -2,22 +2,23 @@ package ui
 
 import (
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
-	"runtime"
+	"strings"
 )
 
 // assetMapping defines a source file and its relative destination path
 type assetMapping struct {
-	srcRelPath  string // relative to componentsDir
-	dstRelPath  string // relative to targetDir
+	srcRelPath string // relative to the source filesystem root
+	dstRelPath string // relative to targetDir
 }
 
 // CopyStyles copies all component CSS assets to the target directory.
-// It auto-discovers the components package location using runtime.Caller.
-// Works both in monorepo and as external package.
+// Styles are read from the embedded filesystem (see SourceFS).
 //
 // The targetDir should be the app's assets/css directory.
 // Component styles will be copied to assets/css/components/
@@ -25,7 +26,7 @@ type assetMapping struct {
 // Infrastructure files (_variables.css, index.css) are not copied as they
 // are build-time imports only.
 //
-// DEPRECATED: Use CopyStylesWithTheme instead for theme support.
+// DEPRECATED: Use NewAssetHandler instead to serve styles from memory.
 //
 // Example:
 //
@@ -38,8 +39,8 @@ func CopyStyles(targetDir string) error {
 }
 
 // CopyStylesWithTheme copies all component CSS assets and generates the app's main.css
-// with the specified theme and font. It auto-disovers the components package location using
-// runtime.Caller. Works both in monorepo and as external package.
+// with the specified theme and font. Styles are read from the embedded filesystem
+// (see SourceFS), so no module source is needed at runtime.
 //
 // The targetDir should be the app's assets/css directory.
 // - Component styles are copied to assets/css/components/
@@ -48,6 +49,8 @@ func CopyStyles(targetDir string) error {
 // Theme options: warm-cream, ocean-deep, forest-night, minimal-light, sunset-glow
 // Font options: default, serif, mono, rounded, condensed
 //
+// DEPRECATED: Use NewAssetHandler instead to serve styles (including main.css) from memory.
+//
 // Example:
 //
 //	cssDir := filepath.Join(dataDir, "assets", "css")
@@ -55,18 +58,11 @@ func CopyStyles(targetDir string) error {
 //	    log.Printf("Warning: Failed to copy component styles: %v", err)
 //	}
 func CopyStylesWithTheme(targetDir, theme string, font string) error {
-	// Get the components package directory using runtime.Caller
-	_, filename, _, ok := runtime.Caller(0)
-	if !ok {
-		return fmt.Errorf("could not determine source file location")
-	}
-
-	componentsDir := filepath.Dir(filename)
-	stylesSrcDir := filepath.Join(componentsDir, "styles")
+	srcFS := SourceFS()
 
 	// Copy component styles to assets/css/components/
 	componentsTargetDir := filepath.Join(targetDir, "components")
-	copied, err := copyDirStyles(stylesSrcDir, componentsTargetDir)
+	copied, err := copyDirStyles(srcFS, "styles", componentsTargetDir)
 	if err != nil {
 		return fmt.Errorf("failed to copy styles: %w", err)
 	}
@@ -78,29 +74,48 @@ func CopyStylesWithTheme(targetDir, theme string, font string) error {
 
 	// Generate main.css at assets/css/app/main.css
 	appCssDir := filepath.Join(targetDir, "app")
-	if err := generateMainCSS(stylesSrcDir, appCssDir, theme, font); err != nil {
+	if err := generateMainCSS(srcFS, appCssDir, theme, font); err != nil {
 		return fmt.Errorf("failed to generate main.css: %w", err)
 	}
 
 	return nil
 }
 
-// generateMainCSS creates the app's main.css by combining:
-// 1. Default theme CSS variables (normalized to :root) from themes/{theme}.css
-// 2. All other theme CSS files for runtime switching via data-theme attribute
-// 3. Base styles from main-base.css (density, reset, typography)
-// 4. Default font attribute on :root
-func generateMainCSS(stylesSrcDir, appCssDir, theme, font string) error {
+// generateMainCSS builds the app's main.css (see buildMainCSS) and writes it to appCssDir.
+func generateMainCSS(srcFS fs.FS, appCssDir, theme, font string) error {
 	// Ensure target directory exists
 	if err := os.MkdirAll(appCssDir, 0755); err != nil {
 		return fmt.Errorf("failed to create app CSS directory: %w", err)
 	}
 
+	combinedCSS, themeCount, err := buildMainCSS(srcFS, theme, font)
+	if err != nil {
+		return err
+	}
+
+	// Write to app/main.css (always overwrites existing file - this is a generated file)
+	mainFile := filepath.Join(appCssDir, "main.css")
+	if err := os.WriteFile(mainFile, combinedCSS, 0644); err != nil {
+		return fmt.Errorf("failed to write main.css: %w", err)
+	}
+
+	log.Printf("Generated main.css with default theme '%s', font '%s', and %d total themes at: %s", theme, font, themeCount, mainFile)
+	return nil
+}
+
+// buildMainCSS creates the app's main.css by combining:
+// 1. Default theme CSS variables (normalized to :root) from themes/{theme}.css
+// 2. All other theme CSS files for runtime switching via data-theme attribute
+// 3. Base styles from main-base.css (density, reset, typography)
+// 4. Default font attribute on :root
+//
+// Returns the generated CSS and the total number of themes included.
+func buildMainCSS(srcFS fs.FS, theme, font string) ([]byte, int, error) {
 	// Read the default theme file
-	themeFile := filepath.Join(stylesSrcDir, "themes", theme+".css")
-	themeCSS, err := os.ReadFile(themeFile)
+	themeFile := path.Join("styles", "themes", theme+".css")
+	themeCSS, err := fs.ReadFile(srcFS, themeFile)
 	if err != nil {
-		return fmt.Errorf("failed to read theme file %s: %w", themeFile, err)
+		return nil, 0, fmt.Errorf("failed to read theme file %s: %w", themeFile, err)
 	}
 
 	// Normalize default theme selector to :root so it always applies as the page default.
@@ -110,9 +125,9 @@ func generateMainCSS(stylesSrcDir, appCssDir, theme, font string) error {
 	themeCSSNormalized := selectorRe.ReplaceAllString(string(themeCSS), ":root")
 
 	// Read all other theme files for runtime switching
-	allThemeFiles, err := filepath.Glob(filepath.Join(stylesSrcDir, "themes", "*.css"))
+	allThemeFiles, err := fs.Glob(srcFS, path.Join("styles", "themes", "*.css"))
 	if err != nil {
-		return fmt.Errorf("failed to list theme files: %w", err)
+		return nil, 0, fmt.Errorf("failed to list theme files: %w", err)
 	}
 
 	// For non-default themes, strip any :root prefix so only [data-theme="..."] applies.
@@ -125,7 +140,7 @@ func generateMainCSS(stylesSrcDir, appCssDir, theme, font string) error {
 		if tf == themeFile {
 			continue // skip the default theme (already included as :root)
 		}
-		css, err := os.ReadFile(tf)
+		css, err := fs.ReadFile(srcFS, tf)
 		if err != nil {
 			log.Printf("Warning: Failed to read theme file %s: %v", tf, err)
 			continue
@@ -135,17 +150,15 @@ func generateMainCSS(stylesSrcDir, appCssDir, theme, font string) error {
 	}
 
 	// Read layout.css (structural variables: dimensions, radii, transitions, z-index)
-	layoutFile := filepath.Join(stylesSrcDir, "layout.css")
-	layoutCSS, err := os.ReadFile(layoutFile)
+	layoutCSS, err := fs.ReadFile(srcFS, path.Join("styles", "layout.css"))
 	if err != nil {
-		return fmt.Errorf("failed to read layout.css: %w", err)
+		return nil, 0, fmt.Errorf("failed to read layout.css: %w", err)
 	}
 
 	// Read main-base.css
-	baseFile := filepath.Join(stylesSrcDir, "main-base.css")
-	baseCSS, err := os.ReadFile(baseFile)
+	baseCSS, err := fs.ReadFile(srcFS, path.Join("styles", "main-base.css"))
 	if err != nil {
-		return fmt.Errorf("failed to read main-base.css: %w", err)
+		return nil, 0, fmt.Errorf("failed to read main-base.css: %w", err)
 	}
 
 	// Add file header to indicate this is auto-generated
@@ -159,23 +172,18 @@ func generateMainCSS(stylesSrcDir, appCssDir, theme, font string) error {
 	// Combine: header, layout vars, default theme (:root), other themes, base styles, font attribute
 	combinedCSS := header + string(layoutCSS) + "\n" + themeCSSNormalized + "\n" + otherThemesCSS + "\n\n" + string(baseCSS) + fontAttrCSS
 
-	// Write to app/main.css (always overwrites existing file - this is a generated file)
-	mainFile := filepath.Join(appCssDir, "main.css")
-	if err := os.WriteFile(mainFile, []byte(combinedCSS), 0644); err != nil {
-		return fmt.Errorf("failed to write main.css: %w", err)
-	}
-
-	log.Printf("Generated main.css with default theme '%s', font '%s', and %d total themes at: %s", theme, font, themeCount+1, mainFile)
-	return nil
+	return []byte(combinedCSS), themeCount + 1, nil
 }
 
 // CopyStaticAssets copies all component JavaScript assets to the target directory.
-// It auto-discovers the components package location using runtime.Caller.
-// Works both in monorepo and as external package.
+// Scripts are read from the embedded filesystem (see SourceFS).
 //
 // The targetDir should be the app's assets/js directory.
 // Component assets will be copied to assets/js/components/
 //
+// DEPRECATED: Use NewAssetHandler instead. The shared partials now load component
+// scripts from AssetPathPrefix (/assets/components/).
+//
 // Example:
 //
 //	assetsDir := filepath.Join(dataDir, "assets", "js")
@@ -183,44 +191,25 @@ func generateMainCSS(stylesSrcDir, appCssDir, theme, font string) error {
 //	    log.Printf("Warning: Failed to copy component assets: %v", err)
 //	}
 func CopyStaticAssets(targetDir string) error {
-	// Get the components package directory using runtime.Caller
-	// This works both in monorepo and when package is external
-	_, filename, _, ok := runtime.Caller(0)
-	if !ok {
-		return fmt.Errorf("could not determine source file location")
-	}
-
-	componentsDir := filepath.Dir(filename)
+	srcFS := SourceFS()
 
 	// All component assets go under a "components" subdirectory for clean separation
 	componentsTargetDir := filepath.Join(targetDir, "components")
 
-	// Define all assets to copy: source relative to componentsDir, destination relative to componentsTargetDir
-	assets := []assetMapping{
-		// Table JS files (to assets/js/components/table/)
-		{srcRelPath: "assets/js/table", dstRelPath: "table"},
-		// Individual component JS files (to assets/js/components/)
-		{srcRelPath: "assets/js/sheet.js", dstRelPath: "sheet.js"},
-		{srcRelPath: "assets/js/help-pane.js", dstRelPath: "help-pane.js"},
-		{srcRelPath: "assets/js/dialog.js", dstRelPath: "dialog.js"},
-	}
-
 	var totalCopied int
 
-	for _, asset := range assets {
-		srcPath := filepath.Join(componentsDir, asset.srcRelPath)
-		dstPath := filepath.Join(componentsTargetDir, asset.dstRelPath)
+	for _, asset := range staticAssets {
+		dstPath := filepath.Join(componentsTargetDir, filepath.FromSlash(asset.dstRelPath))
 
 		// Check if source is a directory or file
-		info, err := os.Stat(srcPath)
+		info, err := statStaticAsset(srcFS, asset)
 		if err != nil {
-			log.Printf("Warning: Source not found, skipping: %s", srcPath)
-			continue
+			return err
 		}
 
 		if info.IsDir() {
 			// Copy all .js files from directory
-			copied, err := copyDirAssets(srcPath, dstPath)
+			copied, err := copyDirAssets(srcFS, asset.srcRelPath, dstPath)
 			if err != nil {
 				log.Printf("Warning: Failed to copy %s: %v", asset.srcRelPath, err)
 				continue
@@ -228,7 +217,11 @@ func CopyStaticAssets(targetDir string) error {
 			totalCopied += copied
 		} else {
 			// Copy single file
-			if err := copyFileAsset(srcPath, dstPath); err != nil {
+			if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+				log.Printf("Warning: Failed to copy %s: %v", asset.srcRelPath, err)
+				continue
+			}
+			if err := copyFileAsset(srcFS, asset.srcRelPath, dstPath); err != nil {
 				log.Printf("Warning: Failed to copy %s: %v", asset.srcRelPath, err)
 				continue
 			}
@@ -244,37 +237,60 @@ func CopyStaticAssets(targetDir string) error {
 	return nil
 }
 
+// staticAssets lists all component JS assets: source relative to the source filesystem,
+// destination relative to the components target directory.
+var staticAssets = []assetMapping{
+	// Table JS files (to assets/js/components/table/)
+	{srcRelPath: "assets/js/table", dstRelPath: "table"},
+	// Individual component JS files (to assets/js/components/)
+	{srcRelPath: "assets/js/sheet.js", dstRelPath: "sheet.js"},
+	{srcRelPath: "assets/js/help-pane.js", dstRelPath: "help-pane.js"},
+	{srcRelPath: "assets/js/dialog.js", dstRelPath: "dialog.js"},
+	{srcRelPath: "assets/js/message.js", dstRelPath: "message.js"},
+	{srcRelPath: "assets/js/sidebar.js", dstRelPath: "sidebar.js"},
+	// Vendored third-party scripts (to assets/js/components/vendor/; see vendor.go)
+	{srcRelPath: "assets/vendor/htmx/htmx-" + HTMX1Release + ".min.js", dstRelPath: "vendor/htmx-" + HTMX1Release + ".min.js"},
+	{srcRelPath: "assets/vendor/htmx/htmx-" + HTMX2Release + ".min.js", dstRelPath: "vendor/htmx-" + HTMX2Release + ".min.js"},
+}
+
+// statStaticAsset stats a staticAssets source. Every listed source must exist: a
+// missing vendored file would otherwise only show up as a 404 on every page.
+func statStaticAsset(srcFS fs.FS, asset assetMapping) (fs.FileInfo, error) {
+	info, err := fs.Stat(srcFS, asset.srcRelPath)
+	if err == nil {
+		return info, nil
+	}
+	if strings.HasPrefix(asset.srcRelPath, "assets/vendor/") {
+		return nil, fmt.Errorf("vendored asset not found: %s (run scripts/vendor-htmx.sh): %w", asset.srcRelPath, err)
+	}
+	return nil, fmt.Errorf("asset not found: %s: %w", asset.srcRelPath, err)
+}
+
 // copyDirStyles copies all .css files from source directory to destination directory.
 // Infrastructure files (_variables.css, index.css) are excluded.
-func copyDirStyles(srcDir, dstDir string) (int, error) {
+func copyDirStyles(srcFS fs.FS, srcDir, dstDir string) (int, error) {
 	// Ensure target directory exists
 	if err := os.MkdirAll(dstDir, 0755); err != nil {
 		return 0, fmt.Errorf("failed to create target directory: %w", err)
 	}
 
-	files, err := filepath.Glob(filepath.Join(srcDir, "*.css"))
+	files, err := fs.Glob(srcFS, path.Join(srcDir, "*.css"))
 	if err != nil {
 		return 0, fmt.Errorf("failed to list source files: %w", err)
 	}
 
-	// Infrastructure files to exclude (build-time imports only)
-	excludeFiles := map[string]bool{
-		"_variables.css": true,
-		"index.css":      true,
-	}
-
 	var copied int
 	for _, srcFile := range files {
-		baseName := filepath.Base(srcFile)
+		baseName := path.Base(srcFile)
 
 		// Skip infrastructure files
-		if excludeFiles[baseName] {
+		if excludedStyles[baseName] {
 			continue
 		}
 
 		dstFile := filepath.Join(dstDir, baseName)
 
-		if err := copyFileAsset(srcFile, dstFile); err != nil {
+		if err := copyFileAsset(srcFS, srcFile, dstFile); err != nil {
 			return copied, err
 		}
 		copied++
@@ -283,14 +299,20 @@ func copyDirStyles(srcDir, dstDir string) (int, error) {
 	return copied, nil
 }
 
+// excludedStyles are infrastructure files that are build-time imports only
+var excludedStyles = map[string]bool{
+	"_variables.css": true,
+	"index.css":      true,
+}
+
 // copyDirAssets copies all .js files from source directory to destination directory
-func copyDirAssets(srcDir, dstDir string) (int, error) {
+func copyDirAssets(srcFS fs.FS, srcDir, dstDir string) (int, error) {
 	// Ensure target directory exists
 	if err := os.MkdirAll(dstDir, 0755); err != nil {
 		return 0, fmt.Errorf("failed to create target directory: %w", err)
 	}
 
-	files, err := filepath.Glob(filepath.Join(srcDir, "*.js"))
+	files, err := fs.Glob(srcFS, path.Join(srcDir, "*.js"))
 	if err != nil {
 		return 0, fmt.Errorf("failed to list source files: %w", err)
 	}
@@ -301,10 +323,10 @@ func copyDirAssets(srcDir, dstDir string) (int, error) {
 
 	var copied int
 	for _, srcFile := range files {
-		baseName := filepath.Base(srcFile)
+		baseName := path.Base(srcFile)
 		dstFile := filepath.Join(dstDir, baseName)
 
-		if err := copyFileAsset(srcFile, dstFile); err != nil {
+		if err := copyFileAsset(srcFS, srcFile, dstFile); err != nil {
 			return copied, err
 		}
 		copied++
@@ -313,9 +335,9 @@ func copyDirAssets(srcDir, dstDir string) (int, error) {
 	return copied, nil
 }
 
-// copyFileAsset copies a single file from src to dst
-func copyFileAsset(src, dst string) error {
-	data, err := os.ReadFile(src)
+// copyFileAsset copies a single file from the source filesystem to dst on disk
+func copyFileAsset(srcFS fs.FS, src, dst string) error {
+	data, err := fs.ReadFile(srcFS, src)
 	if err != nil {
 		return fmt.Errorf("failed to read: %w", err)
 	}
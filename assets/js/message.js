@@ -0,0 +1,236 @@
+/**
+ * Message Format - ICU-style message formatting (matches ui.FormatMessage in Go)
+ *
+ * Supported syntax:
+ *   {name}                                   argument value
+ *   {{name}}                                 same (legacy placeholder)
+ *   {count, plural, =0 {none} one {# item} other {# items}}
+ *   {count, plural, offset:1 one {...} other {...}}
+ *   {role, select, admin {Administrator} other {Member}}
+ *   '{literal}'                              quoted text ('' is an apostrophe)
+ *
+ * Plural categories come from Intl.PluralRules for the locale
+ * (default: <html lang>, falling back to "en"). Languages ui.PluralCategory
+ * has no rules for use "en", so server and browser pick the same category.
+ *
+ * Usage:
+ *   MessageFormat.format('{count, plural, one {# item} other {# items}} selected', { count: 3 })
+ *   MessageFormat.displays('+{count} more', 'count')  // true
+ */
+
+(function() {
+    'use strict';
+
+    const cache = new Map();       // pattern -> parsed nodes
+    const pluralRules = new Map(); // locale -> Intl.PluralRules
+
+    // Languages with plural rules in ui.PluralCategory (others use English)
+    const pluralLanguages = new Set([
+        'ja', 'zh', 'ko', 'vi', 'th', 'id', 'ms', 'lo', 'my', 'km',
+        'en', 'de', 'nl', 'sv', 'da', 'nb', 'no', 'fi', 'et', 'el', 'hu', 'tr', 'bg',
+        'fr', 'es', 'it', 'pt', 'ru', 'uk', 'be', 'pl', 'cs', 'sk', 'ar', 'he'
+    ]);
+
+    function defaultLocale() {
+        return document.documentElement.lang || 'en';
+    }
+
+    function format(pattern, args, locale) {
+        if (pattern == null) return '';
+        let nodes;
+        try {
+            nodes = parse(String(pattern));
+        } catch (err) {
+            console.warn('[MessageFormat] Invalid message:', pattern, err.message);
+            return String(pattern);
+        }
+        return render(nodes, args || {}, locale || defaultLocale(), 0, false);
+    }
+
+    // displays reports whether a pattern prints an argument's value ({name}, or # in
+    // its plural cases), as opposed to only choosing a case with it
+    function displays(pattern, name) {
+        const walk = (nodes, pluralName) => nodes.some(node => {
+            if (typeof node !== 'object') return false;
+            if (node.type === 'pound') return pluralName === name;
+            if (node.type === 'arg') return node.name === name;
+            const inner = node.type === 'plural' ? node.name : pluralName;
+            return Object.values(node.cases).some(nodes => walk(nodes, inner));
+        });
+        try {
+            return walk(parse(String(pattern)), null);
+        } catch (err) {
+            return false;
+        }
+    }
+
+    function plural(locale, n) {
+        if (!pluralRules.has(locale)) {
+            const language = String(locale).split(/[-_]/)[0].toLowerCase();
+            let rules;
+            try {
+                rules = new Intl.PluralRules(pluralLanguages.has(language) ? String(locale).replace(/_/g, '-') : 'en');
+            } catch (err) {
+                rules = new Intl.PluralRules('en');
+            }
+            pluralRules.set(locale, rules);
+        }
+        return pluralRules.get(locale).select(n);
+    }
+
+    function render(nodes, values, locale, number, inPlural) {
+        let out = '';
+        nodes.forEach(node => {
+            if (typeof node === 'string') {
+                out += node;
+            } else if (node.type === 'pound') {
+                out += inPlural ? String(number) : '#';
+            } else if (node.type === 'arg') {
+                out += node.name in values ? String(values[node.name]) : '{' + node.name + '}';
+            } else if (node.type === 'select') {
+                const selected = node.cases[String(values[node.name])] || node.cases.other;
+                out += render(selected, values, locale, number, inPlural);
+            } else {
+                const count = Number(values[node.name]) || 0;
+                const n = count - node.offset;
+                const selected = node.cases['=' + count] || node.cases[plural(locale, n)] || node.cases.other;
+                out += render(selected, values, locale, n, true);
+            }
+        });
+        return out;
+    }
+
+    function parse(src) {
+        if (cache.has(src)) return cache.get(src);
+        const p = { src, pos: 0 };
+        const nodes = parseNodes(p, false, 0);
+        cache.set(src, nodes);
+        return nodes;
+    }
+
+    function parseNodes(p, inPlural, depth) {
+        const nodes = [];
+        let text = '';
+        const flush = () => {
+            if (text) nodes.push(text);
+            text = '';
+        };
+
+        while (p.pos < p.src.length) {
+            const c = p.src[p.pos];
+            if (c === '\'') {
+                text += readQuoted(p, inPlural);
+            } else if (c === '#' && inPlural) {
+                flush();
+                nodes.push({ type: 'pound' });
+                p.pos++;
+            } else if (c === '}') {
+                if (depth === 0) throw new Error('unexpected } at ' + p.pos);
+                flush();
+                return nodes;
+            } else if (c === '{') {
+                flush();
+                nodes.push(parseArgument(p, depth));
+            } else {
+                text += c;
+                p.pos++;
+            }
+        }
+
+        if (depth > 0) throw new Error('unclosed {');
+        flush();
+        return nodes;
+    }
+
+    function readQuoted(p, inPlural) {
+        p.pos++;
+        const next = p.src[p.pos];
+        if (next === '\'') {
+            p.pos++;
+            return '\'';
+        }
+        if (!(next === '{' || next === '}' || (inPlural && next === '#'))) {
+            return '\'';
+        }
+        let text = '';
+        while (p.pos < p.src.length) {
+            const c = p.src[p.pos++];
+            if (c !== '\'') {
+                text += c;
+            } else if (p.src[p.pos] === '\'') {
+                text += '\'';
+                p.pos++;
+            } else {
+                break;
+            }
+        }
+        return text;
+    }
+
+    function parseArgument(p, depth) {
+        // Legacy {{name}} placeholder
+        const legacy = /^\{\{\s*([\w.-]+)\s*\}\}/.exec(p.src.slice(p.pos));
+        if (legacy) {
+            p.pos += legacy[0].length;
+            return { type: 'arg', name: legacy[1] };
+        }
+
+        p.pos++; // {
+        const name = readMatch(p, /^\s*([\w.-]+)\s*/);
+        if (!name) throw new Error('expected argument name at ' + p.pos);
+        if (consume(p, '}')) return { type: 'arg', name };
+        if (!consume(p, ',')) throw new Error('expected , or } at ' + p.pos);
+
+        const kind = readMatch(p, /^\s*(\w+)\s*/);
+        if (kind === 'number') {
+            const end = p.src.indexOf('}', p.pos);
+            if (end < 0) throw new Error('unclosed {');
+            p.pos = end + 1;
+            return { type: 'arg', name };
+        }
+        if (kind !== 'plural' && kind !== 'select') throw new Error('unknown argument type ' + kind);
+        if (!consume(p, ',')) throw new Error('expected , at ' + p.pos);
+
+        const node = { type: kind, name, offset: 0, cases: {} };
+        for (;;) {
+            readMatch(p, /^(\s*)/);
+            if (consume(p, '}')) break;
+            if (p.pos >= p.src.length) throw new Error('unclosed {');
+
+            const key = readMatch(p, /^([^\s{}]+)/);
+            if (!key) throw new Error('expected case selector at ' + p.pos);
+            if (kind === 'plural' && key.startsWith('offset:')) {
+                node.offset = Number(key.slice(7)) || 0;
+                continue;
+            }
+
+            readMatch(p, /^(\s*)/);
+            if (!consume(p, '{')) throw new Error('expected { after ' + key);
+            node.cases[key] = parseNodes(p, kind === 'plural', depth + 1);
+            p.pos++; // }
+        }
+
+        if (!node.cases.other) throw new Error(kind + ' argument ' + name + ' has no other case');
+        return node;
+    }
+
+    function readMatch(p, re) {
+        const m = re.exec(p.src.slice(p.pos));
+        if (!m) return '';
+        p.pos += m[0].length;
+        return m[1];
+    }
+
+    function consume(p, c) {
+        if (p.src[p.pos] !== c) return false;
+        p.pos++;
+        return true;
+    }
+
+    // Expose module
+    window.MessageFormat = {
+        format,
+        displays,
+        plural
+    };
+})();
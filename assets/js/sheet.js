@@ -255,6 +255,16 @@
             }
         });
 
+        // Declarative triggers: data-sheet-open="Title" and data-sheet-close
+        document.addEventListener('click', function(e) {
+            const opener = e.target.closest('[data-sheet-open]');
+            if (opener) {
+                open(opener.dataset.sheetOpen);
+            } else if (e.target.closest('[data-sheet-close]')) {
+                close();
+            }
+        });
+
         // Escape key to close
         document.addEventListener('keydown', function(e) {
             if (e.key === 'Escape' && isOpen) {
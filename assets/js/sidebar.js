@@ -0,0 +1,301 @@
+/**
+ * Sidebar - App Navigation
+ *
+ * Behaviour for the "sidebar" component (components/sidebar.html):
+ * - Collapsible sections ([data-sidebar-section-toggle]), persisted in localStorage
+ * - Collapse to icons ([data-sidebar-collapse]), persisted in the sidebar-collapsed
+ *   cookie so the server renders the same state (ui.IsSidebarCollapsed)
+ * - Sub-item toggles ([data-sidebar-item-toggle])
+ * - App switcher and user menu dropdowns ([data-sidebar-menu])
+ * - Mobile off-canvas mode: [data-sidebar-open] opens, [data-sidebar-close],
+ *   the backdrop and Escape close
+ *
+ * Events are delegated from the document, so sidebars swapped in by HTMX work
+ * without re-initialisation; persisted section state is re-applied after swaps.
+ */
+
+(function() {
+    'use strict';
+
+    const COLLAPSED_COOKIE = 'sidebar-collapsed';
+    const SECTIONS_KEY = 'sidebar-sections';
+    const MOBILE_QUERY = '(max-width: 64rem)';
+
+    function getSidebar(id) {
+        return id ? document.getElementById(id) : document.querySelector('[data-sidebar]');
+    }
+
+    function getBackdrop(sidebar) {
+        const next = sidebar && sidebar.nextElementSibling;
+        return next && next.classList.contains('sidebar-backdrop') ? next : null;
+    }
+
+    // ========================================
+    // Persisted state
+    // ========================================
+
+    function loadSections() {
+        try {
+            return JSON.parse(localStorage.getItem(SECTIONS_KEY)) || {};
+        } catch (err) {
+            return {};
+        }
+    }
+
+    function saveSection(key, collapsed) {
+        if (!key) return;
+        const sections = loadSections();
+        sections[key] = collapsed;
+        try {
+            localStorage.setItem(SECTIONS_KEY, JSON.stringify(sections));
+        } catch (err) {
+            // Storage unavailable (private mode); state lasts for the page only
+        }
+    }
+
+    function setCollapsedCookie(collapsed) {
+        document.cookie = COLLAPSED_COOKIE + '=' + (collapsed ? '1' : '0') +
+            '; path=/; max-age=31536000; samesite=lax';
+    }
+
+    // ========================================
+    // Sections and items
+    // ========================================
+
+    function setSectionCollapsed(section, collapsed) {
+        const toggle = section.querySelector('[data-sidebar-section-toggle]');
+        const items = section.querySelector('.sidebar-items');
+        section.classList.toggle('collapsed', collapsed);
+        if (toggle) toggle.setAttribute('aria-expanded', String(!collapsed));
+        if (items) items.hidden = collapsed;
+    }
+
+    function toggleSection(section) {
+        const collapsed = !section.classList.contains('collapsed');
+        setSectionCollapsed(section, collapsed);
+        saveSection(section.dataset.section, collapsed);
+    }
+
+    // applySections restores the persisted section state. Sections holding the
+    // active item stay open so the current page is always visible.
+    function applySections(root) {
+        const sections = loadSections();
+        (root || document).querySelectorAll('.sidebar-section.collapsible').forEach(section => {
+            const key = section.dataset.section;
+            if (!(key in sections)) return;
+            if (sections[key] && section.querySelector('.sidebar-item.active')) return;
+            setSectionCollapsed(section, sections[key]);
+        });
+    }
+
+    function toggleItem(item) {
+        const expanded = !item.classList.contains('expanded');
+        const toggle = item.querySelector(':scope > [data-sidebar-item-toggle]');
+        const subitems = item.querySelector(':scope > .sidebar-subitems');
+        item.classList.toggle('expanded', expanded);
+        if (toggle) toggle.setAttribute('aria-expanded', String(expanded));
+        if (subitems) subitems.hidden = !expanded;
+    }
+
+    // ========================================
+    // Collapse to icons
+    // ========================================
+
+    function setCollapsed(sidebar, collapsed) {
+        if (!sidebar) return;
+        sidebar.classList.toggle('collapsed', collapsed);
+        const button = sidebar.querySelector('[data-sidebar-collapse]');
+        if (button) {
+            button.setAttribute('aria-expanded', String(!collapsed));
+            const label = collapsed ? button.dataset.labelExpand : button.dataset.labelCollapse;
+            if (label) button.setAttribute('aria-label', label);
+        }
+        closeMenus();
+        setCollapsedCookie(collapsed);
+    }
+
+    function toggleCollapsed(sidebar) {
+        if (!sidebar) return;
+        setCollapsed(sidebar, !sidebar.classList.contains('collapsed'));
+    }
+
+    // ========================================
+    // Dropdown menus
+    // ========================================
+
+    function openMenu(menu) {
+        closeMenus(menu);
+        const toggle = menu.querySelector('[data-sidebar-menu-toggle]');
+        const list = menu.querySelector('.sidebar-menu');
+        if (!list) return;
+        menu.classList.add('open');
+        list.hidden = false;
+        if (toggle) toggle.setAttribute('aria-expanded', 'true');
+        const first = list.querySelector('[role="menuitem"]');
+        if (first) first.focus();
+    }
+
+    function closeMenu(menu) {
+        const toggle = menu.querySelector('[data-sidebar-menu-toggle]');
+        const list = menu.querySelector('.sidebar-menu');
+        menu.classList.remove('open');
+        if (list) list.hidden = true;
+        if (toggle) toggle.setAttribute('aria-expanded', 'false');
+    }
+
+    function closeMenus(except) {
+        document.querySelectorAll('[data-sidebar-menu].open').forEach(menu => {
+            if (menu !== except) closeMenu(menu);
+        });
+    }
+
+    // ========================================
+    // Off-canvas (mobile)
+    // ========================================
+
+    function isMobile() {
+        return window.matchMedia(MOBILE_QUERY).matches;
+    }
+
+    function open(id) {
+        const sidebar = getSidebar(id);
+        if (!sidebar) return;
+        sidebar.classList.add('open');
+        document.body.classList.add('sidebar-open');
+        const backdrop = getBackdrop(sidebar);
+        if (backdrop) backdrop.hidden = false;
+        document.querySelectorAll('[data-sidebar-open]').forEach(btn => {
+            if ((btn.dataset.sidebarOpen || sidebar.id) === sidebar.id) btn.setAttribute('aria-expanded', 'true');
+        });
+        const focusable = sidebar.querySelector('a, button');
+        if (focusable) focusable.focus();
+    }
+
+    function close(id) {
+        const sidebar = getSidebar(id);
+        if (!sidebar || !sidebar.classList.contains('open')) return;
+        sidebar.classList.remove('open');
+        document.body.classList.remove('sidebar-open');
+        const backdrop = getBackdrop(sidebar);
+        if (backdrop) backdrop.hidden = true;
+        closeMenus();
+        let opener = null;
+        document.querySelectorAll('[data-sidebar-open]').forEach(btn => {
+            if ((btn.dataset.sidebarOpen || sidebar.id) === sidebar.id) {
+                btn.setAttribute('aria-expanded', 'false');
+                opener = opener || btn;
+            }
+        });
+        if (opener) opener.focus();
+    }
+
+    // ========================================
+    // Event delegation
+    // ========================================
+
+    function handleClick(e) {
+        const target = e.target;
+
+        const opener = target.closest('[data-sidebar-open]');
+        if (opener) {
+            open(opener.dataset.sidebarOpen);
+            return;
+        }
+
+        const closer = target.closest('[data-sidebar-close]');
+        if (closer) {
+            const sidebar = closer.closest('[data-sidebar]') || closer.previousElementSibling;
+            close(sidebar && sidebar.id);
+            return;
+        }
+
+        const collapse = target.closest('[data-sidebar-collapse]');
+        if (collapse) {
+            toggleCollapsed(collapse.closest('[data-sidebar]'));
+            return;
+        }
+
+        const sectionToggle = target.closest('[data-sidebar-section-toggle]');
+        if (sectionToggle) {
+            toggleSection(sectionToggle.closest('.sidebar-section'));
+            return;
+        }
+
+        const itemToggle = target.closest('[data-sidebar-item-toggle]');
+        if (itemToggle) {
+            toggleItem(itemToggle.closest('.sidebar-item'));
+            return;
+        }
+
+        const menuToggle = target.closest('[data-sidebar-menu-toggle]');
+        if (menuToggle) {
+            const menu = menuToggle.closest('[data-sidebar-menu]');
+            if (menu.classList.contains('open')) {
+                closeMenu(menu);
+            } else {
+                openMenu(menu);
+            }
+            return;
+        }
+
+        // Outside click closes open menus
+        if (!target.closest('[data-sidebar-menu]')) closeMenus();
+
+        // Following a link closes the off-canvas sidebar
+        const link = target.closest('[data-sidebar] a[href]');
+        if (link && isMobile()) close(link.closest('[data-sidebar]').id);
+    }
+
+    function handleKeydown(e) {
+        if (e.key !== 'Escape') return;
+        const menu = document.querySelector('[data-sidebar-menu].open');
+        if (menu) {
+            closeMenu(menu);
+            const toggle = menu.querySelector('[data-sidebar-menu-toggle]');
+            if (toggle) toggle.focus();
+            return;
+        }
+        const sidebar = document.querySelector('[data-sidebar].open');
+        if (sidebar) close(sidebar.id);
+    }
+
+    /**
+     * Initialize
+     */
+    function init() {
+        if (document._sidebarInitialized) return;
+        document._sidebarInitialized = true;
+
+        document.addEventListener('click', handleClick);
+        document.addEventListener('keydown', handleKeydown);
+        document.body.addEventListener('htmx:afterSwap', function(e) {
+            applySections(e.target);
+        });
+
+        // Leaving the mobile layout resets the off-canvas state
+        window.matchMedia(MOBILE_QUERY).addEventListener('change', function(e) {
+            if (!e.matches) {
+                const sidebar = document.querySelector('[data-sidebar].open');
+                if (sidebar) close(sidebar.id);
+            }
+        });
+
+        applySections(document);
+    }
+
+    // Initialize when DOM is ready
+    if (document.readyState === 'loading') {
+        document.addEventListener('DOMContentLoaded', init);
+    } else {
+        init();
+    }
+
+    // Expose API for external use
+    window.Sidebar = {
+        open,
+        close,
+        collapse: (id) => setCollapsed(getSidebar(id), true),
+        expand: (id) => setCollapsed(getSidebar(id), false),
+        toggleCollapsed: (id) => toggleCollapsed(getSidebar(id))
+    };
+})();
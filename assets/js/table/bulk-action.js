@@ -6,7 +6,8 @@
  *
  * - data-endpoint: POST endpoint URL
  * - data-confirm-title: Confirmation dialog title
- * - data-confirm-message: Confirmation message (use {{count}} placeholder)
+ * - data-confirm-message: Confirmation message, formatted with count (see message.js),
+ *   e.g. "Delete {count, plural, one {# pay item} other {# pay items}}?" ({{count}} also works)
  * - data-extra-params: JSON string of extra form parameters
  *
  * Uses the new HTMX-based dialog system instead of window.TableDialog
@@ -65,8 +66,11 @@
 
         // Read configuration from data attributes
         const confirmTitle = actionBtn.dataset.confirmTitle || 'Confirm Action';
-        const confirmMessage = (actionBtn.dataset.confirmMessage || `Are you sure you want to ${action} ${count} item(s)?`)
-            .replace(/\{\{count\}\}/g, count);
+        const confirmTemplate = actionBtn.dataset.confirmMessage ||
+            `Are you sure you want to ${action} {count, plural, one {# item} other {# items}}?`;
+        const confirmMessage = window.MessageFormat
+            ? MessageFormat.format(confirmTemplate, { count })
+            : confirmTemplate.replace(/\{\{count\}\}/g, count);
         const extraParamsJSON = actionBtn.dataset.extraParams;
 
         // Determine variant from button classes
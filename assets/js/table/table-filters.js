@@ -34,6 +34,11 @@
             // Get columns from table headers
             const columns = getTableColumns(table);
 
+            // Server mode: show the filters the page was rendered with (e.g., restored from the URL)
+            if (isServerMode) {
+                restoreFilterConditions(conditionsContainer, columns, tableCard.dataset.filters);
+            }
+
             // Add condition button
             if (addBtn) {
                 addBtn.addEventListener('click', () => {
@@ -197,6 +202,26 @@
         container.appendChild(row);
     }
 
+    /**
+     * Rebuild the condition rows from encoded filters (the table-card's data-filters).
+     *
+     * @param {HTMLElement} container - The .filter-conditions element
+     * @param {Array} columns - Filterable columns (see getTableColumns)
+     * @param {string} encoded - Base64 JSON filter conditions ("" = none)
+     */
+    function restoreFilterConditions(container, columns, encoded) {
+        container.innerHTML = '';
+        if (!encoded || !window.TableServer) return;
+
+        window.TableServer.decodeFilters(encoded).forEach(condition => {
+            addFilterCondition(container, columns, condition.logic === 'or' ? 'or' : 'and');
+            const row = container.lastElementChild;
+            row.querySelector('.filter-column').value = condition.column || '';
+            row.querySelector('.filter-operator').value = condition.operator || 'contains';
+            row.querySelector('.filter-value').value = condition.value || '';
+        });
+    }
+
     function getFilterConditions(container) {
         const conditions = [];
         const rows = container.querySelectorAll('.filter-row');
@@ -315,6 +340,7 @@
         initFilters,
         getTableColumns,
         addFilterCondition,
+        restoreFilterConditions,
         getFilterConditions,
         applyFilters,
         clearFilters
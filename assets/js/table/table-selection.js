@@ -187,6 +187,7 @@
         if (selectedCountEl) {
             selectedCountEl.textContent = count;
         }
+        updateSelectedLabel(card, count);
 
         // Show/hide bulk toolbar based on selection
         if (count > 0) {
@@ -252,6 +253,23 @@
         });
     }
 
+    // Format the toolbar label (BulkActionsConfig.SelectedLabel, a message with a count
+    // argument). Labels that print the count themselves ("{count} selected") hide the bold counter.
+    function updateSelectedLabel(card, count) {
+        const wrapper = card.querySelector('.bulk-selected-count[data-message]');
+        if (!wrapper || !window.MessageFormat) return;
+
+        const message = wrapper.dataset.message;
+        const labelEl = wrapper.querySelector('.selected-label');
+        const counterEl = wrapper.querySelector('strong');
+        if (labelEl) {
+            labelEl.textContent = MessageFormat.format(message, { count });
+        }
+        if (counterEl) {
+            counterEl.hidden = MessageFormat.displays(message, 'count');
+        }
+    }
+
     function clearAllSelections(table, card, selectedIds, selectedCountEl, selectAllCheckbox) {
         console.log('[TableSelection] clearAllSelections called - selectedIds before:', Array.from(selectedIds));
         selectedIds.clear();
@@ -270,6 +288,7 @@
         if (selectedCountEl) {
             selectedCountEl.textContent = '0';
         }
+        updateSelectedLabel(card, 0);
 
         card.setAttribute('data-bulk-mode', 'false');
         console.log('[TableSelection] clearAllSelections complete - data-bulk-mode set to false');
@@ -126,6 +126,8 @@
             } else {
                 params.delete('filters');
             }
+        } else if (tableCard.dataset.filters) {
+            params.set('filters', tableCard.dataset.filters);
         }
 
         // Rebuild URL with updated params
@@ -184,6 +186,11 @@
         const tableId = tableCard.id || '';
         const baseId = tableId.replace('-card', '');
 
+        // Keep the active filters for the next request (a targeted swap keeps this card)
+        if (overrides.filters !== undefined) {
+            tableCard.dataset.filters = overrides.filters;
+        }
+
         if (useTargetedSwap) {
             // Targeted swap via fetch + DOMParser.
             // We cannot use htmx.ajax() here because HTMX parses the response by
@@ -287,6 +294,11 @@
      * @param {Object} overrides - The overrides that were just applied
      */
     function updateBrowserURL(tableCard, overrides) {
+        if (tableCard.dataset.urlState === 'true') {
+            updateTableStateURL(tableCard, overrides);
+            return;
+        }
+
         const browserUrl = new URL(window.location.pathname, window.location.origin);
 
         // Merge current data-attributes with overrides to get final state
@@ -310,6 +322,52 @@
         history.replaceState(null, '', browserUrl.toString());
     }
 
+    /**
+     * Update the table's namespaced state params ("<id>.page", "<id>.search", ...)
+     * in the browser URL, keeping every other param, including other tables' state.
+     * Used for tables rendered with URLState (data-url-state); the server restores
+     * the state with types.ParseTableState.
+     *
+     * @param {HTMLElement} tableCard - The table-card element
+     * @param {Object} overrides - The overrides that were just applied
+     */
+    function updateTableStateURL(tableCard, overrides) {
+        const prefix = tableCard.id.replace(/-card$/, '') + '.';
+        const browserUrl = new URL(window.location.href);
+        Array.from(browserUrl.searchParams.keys()).forEach(key => {
+            if (key.startsWith(prefix)) browserUrl.searchParams.delete(key);
+        });
+
+        const state = {};
+        if (getPaginationMode(tableCard) === 'cursor') {
+            if (overrides.cursor !== undefined) {
+                state.cursor = overrides.cursor;
+                state.curdir = overrides.cursorDirection || '';
+            } else if (overrides.cursorDirection === 'next' && tableCard.dataset.nextCursor) {
+                state.cursor = tableCard.dataset.nextCursor;
+                state.curdir = 'next';
+            } else if (overrides.cursorDirection === 'prev' && tableCard.dataset.prevCursor) {
+                state.cursor = tableCard.dataset.prevCursor;
+                state.curdir = 'prev';
+            }
+        } else {
+            const page = overrides.page !== undefined ? overrides.page : (parseInt(tableCard.dataset.currentPage) || 1);
+            if (page > 1) state.page = page;
+        }
+        state.size = overrides.size !== undefined ? overrides.size : (tableCard.dataset.pageSize || '');
+        state.search = overrides.search !== undefined ? overrides.search : (tableCard.dataset.search || '');
+        state.sort = overrides.sort !== undefined ? overrides.sort : (tableCard.dataset.sortColumn || '');
+        if (state.sort) {
+            state.dir = overrides.dir !== undefined ? overrides.dir : (tableCard.dataset.sortDirection || 'asc');
+        }
+        state.filters = overrides.filters !== undefined ? overrides.filters : (tableCard.dataset.filters || '');
+
+        Object.keys(state).forEach(name => {
+            if (state[name] !== '') browserUrl.searchParams.set(prefix + name, state[name]);
+        });
+        history.replaceState(null, '', browserUrl.toString());
+    }
+
     /**
      * Encode filter conditions to base64 JSON for server transmission.
      *
@@ -318,7 +376,10 @@
      */
     function encodeFilters(conditions) {
         try {
-            const json = JSON.stringify(conditions);
+            // Escape non-ASCII characters: btoa() only accepts Latin-1
+            const json = JSON.stringify(conditions).replace(/[\u007f-\uffff]/g, function(c) {
+                return '\\u' + c.charCodeAt(0).toString(16).padStart(4, '0');
+            });
             return btoa(json);
         } catch (e) {
             console.error('[TableServer] Error encoding filters:', e);
@@ -352,7 +413,7 @@
     function applyPaginationMeta(card, meta) {
         const attrs = ['currentPage', 'pageSize', 'totalRows', 'search',
                        'sortColumn', 'sortDirection', 'hasNext', 'hasPrev',
-                       'nextCursor', 'prevCursor'];
+                       'nextCursor', 'prevCursor', 'filters'];
         attrs.forEach(attr => {
             const val = meta.dataset[attr];
             if (val !== undefined) {
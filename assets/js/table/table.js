@@ -72,6 +72,14 @@
         }
     }
 
+    // Chip overflow toggle (delegated once, so it survives table swaps)
+    document.addEventListener('click', function(e) {
+        const toggle = e.target.closest('.chip-expand-toggle');
+        if (toggle) {
+            toggle.parentElement.classList.toggle('expanded');
+        }
+    });
+
     // Initialize when DOM is ready
     if (document.readyState === 'loading') {
         document.addEventListener('DOMContentLoaded', init);
@@ -0,0 +1,378 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync/atomic"
+)
+
+// tableScriptOrder lists the table JS modules in dependency order.
+// Must match the load order documented in partials/table-scripts.html.
+var tableScriptOrder = []string{
+	"table-core.js",
+	"table-server.js",
+	"table-dropdowns.js",
+	"table-search.js",
+	"table-sort.js",
+	"table-columns.js",
+	"table-filters.js",
+	"table-export.js",
+	"table-density.js",
+	"table-pagination.js",
+	"table-selection.js",
+	"table-actions.js",
+	"bulk-action.js",
+	"table.js",
+}
+
+// tableBundleName is the bundle's name relative to AssetPathPrefix
+const tableBundleName = "table/table.bundle.js"
+
+// productionMode switches the shared partials to bundled scripts
+var productionMode atomic.Bool
+
+// SetProductionMode enables or disables production mode.
+// In production mode the table-scripts partial loads the single minified table bundle
+// (components/table/table.bundle.js) instead of the individual modules.
+// Defaults to false so the unminified modules are easy to debug during development.
+func SetProductionMode(enabled bool) {
+	productionMode.Store(enabled)
+}
+
+// IsProductionMode reports whether production mode is enabled
+func IsProductionMode() bool {
+	return productionMode.Load()
+}
+
+// jsBundle is the result of bundling several scripts
+type jsBundle struct {
+	js        []byte // minified bundle, ending with a sourceMappingURL comment
+	sourceMap []byte // source map (v3) mapping bundle lines back to the modules
+}
+
+// bundleScripts concatenates the scripts in dir (in the given order), minifies them and
+// builds a source map. bundleName is the bundle's path relative to AssetPathPrefix;
+// source map sources are relative to the bundle so browsers load them from the same directory.
+func bundleScripts(srcFS fs.FS, dir string, files []string, bundleName string) (*jsBundle, error) {
+	var js bytes.Buffer
+	var mappings strings.Builder
+	var prev sourceMapSegment
+	outLine := 0
+
+	for fileIdx, file := range files {
+		src, err := fs.ReadFile(srcFS, path.Join(dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		minified, lineMap := minifyJS(src)
+		if len(minified) == 0 {
+			continue
+		}
+
+		for i, srcLine := range lineMap {
+			if outLine > 0 || i > 0 {
+				mappings.WriteByte(';')
+			}
+			seg := sourceMapSegment{source: fileIdx, line: srcLine}
+			seg.encode(&mappings, prev)
+			prev = seg
+			outLine++
+		}
+
+		js.Write(minified)
+		// Each module is an IIFE; the semicolon keeps "})()" followed by "(function"
+		// from being parsed as a call
+		js.WriteString(";\n")
+	}
+
+	mapName := path.Base(bundleName) + ".map"
+	js.WriteString("//# sourceMappingURL=" + mapName + "\n")
+
+	sourceMap, err := json.Marshal(struct {
+		Version  int      `json:"version"`
+		File     string   `json:"file"`
+		Sources  []string `json:"sources"`
+		Names    []string `json:"names"`
+		Mappings string   `json:"mappings"`
+	}{
+		Version:  3,
+		File:     path.Base(bundleName),
+		Sources:  files,
+		Names:    []string{},
+		Mappings: mappings.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode source map: %w", err)
+	}
+
+	return &jsBundle{js: js.Bytes(), sourceMap: sourceMap}, nil
+}
+
+// sourceMapSegment is a single source map mapping at generated column 0
+type sourceMapSegment struct {
+	source int // index into sources
+	line   int // 0-based source line
+}
+
+// encode writes the segment as VLQ fields relative to prev.
+// Fields: generated column, source index, source line, source column.
+func (s sourceMapSegment) encode(b *strings.Builder, prev sourceMapSegment) {
+	writeVLQ(b, 0)
+	writeVLQ(b, s.source-prev.source)
+	writeVLQ(b, s.line-prev.line)
+	writeVLQ(b, 0)
+}
+
+const vlqAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// writeVLQ writes a base64 VLQ encoded value as used by source maps
+func writeVLQ(b *strings.Builder, value int) {
+	v := value << 1
+	if value < 0 {
+		v = (-value << 1) | 1
+	}
+	for {
+		digit := v & 31
+		v >>= 5
+		if v > 0 {
+			digit |= 32
+		}
+		b.WriteByte(vlqAlphabet[digit])
+		if v == 0 {
+			return
+		}
+	}
+}
+
+// minifyJS strips comments, indentation, blank lines and redundant whitespace from
+// JavaScript source. Line breaks between statements are kept so automatic semicolon
+// insertion behaves exactly as in the source. String, template and regex literals are
+// copied verbatim.
+//
+// lineMap[i] is the 0-based source line output line i starts on.
+func minifyJS(src []byte) (out []byte, lineMap []int) {
+	var b bytes.Buffer
+	n := len(src)
+	line := 0
+	lineStart := true     // nothing emitted on the current output line yet
+	pendingSpace := false // whitespace was skipped since the last emitted token
+	var lastTok byte      // last significant character emitted ('"' after a literal)
+	var lastWord strings.Builder
+
+	// startLine records the source line for a new output line
+	startLine := func() {
+		if lineStart {
+			lineMap = append(lineMap, line)
+			lineStart = false
+		}
+	}
+
+	// emitLiteral copies a literal verbatim, tracking any line breaks inside it
+	emitLiteral := func(lit []byte) {
+		startLine()
+		if pendingSpace && isJSWordByte(lastTok) {
+			b.WriteByte(' ')
+		}
+		pendingSpace = false
+		for _, c := range lit {
+			b.WriteByte(c)
+			if c == '\n' {
+				line++
+				lineMap = append(lineMap, line)
+			}
+		}
+		lastTok = '"'
+		lastWord.Reset()
+	}
+
+	newline := func() {
+		if !lineStart {
+			b.WriteByte('\n')
+			lineStart = true
+		}
+		pendingSpace = false
+	}
+
+	for i := 0; i < n; {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			newline()
+			i++
+
+		case c == ' ' || c == '\t' || c == '\r':
+			pendingSpace = true
+			i++
+
+		case c == '/' && i+1 < n && src[i+1] == '/':
+			for i < n && src[i] != '\n' {
+				i++
+			}
+
+		case c == '/' && i+1 < n && src[i+1] == '*':
+			end := bytes.Index(src[i+2:], []byte("*/"))
+			if end < 0 {
+				end = n - i - 2
+			}
+			comment := src[i : i+2+end]
+			i += 2 + end + 2
+			if breaks := bytes.Count(comment, []byte("\n")); breaks > 0 {
+				line += breaks
+				newline()
+			} else {
+				pendingSpace = true
+			}
+
+		case c == '\'' || c == '"' || c == '`':
+			end := scanJSLiteral(src, i)
+			emitLiteral(src[i:end])
+			i = end
+
+		case c == '/' && jsRegexAllowed(lastTok, lastWord.String()):
+			end := scanJSRegex(src, i)
+			emitLiteral(src[i:end])
+			i = end
+
+		default:
+			startLine()
+			spaced := pendingSpace
+			if spaced && jsNeedsSpace(lastTok, c) {
+				b.WriteByte(' ')
+			}
+			pendingSpace = false
+			b.WriteByte(c)
+			if isJSWordByte(c) {
+				if spaced || !isJSWordByte(lastTok) {
+					lastWord.Reset()
+				}
+				lastWord.WriteByte(c)
+			} else {
+				lastWord.Reset()
+			}
+			lastTok = c
+			i++
+		}
+	}
+
+	return bytes.TrimRight(b.Bytes(), "\n"), lineMap
+}
+
+// scanJSLiteral returns the index just past the string or template literal starting at i
+func scanJSLiteral(src []byte, i int) int {
+	quote := src[i]
+	n := len(src)
+	i++
+	for i < n {
+		switch c := src[i]; {
+		case c == '\\':
+			i += 2
+		case c == quote:
+			return i + 1
+		case quote == '`' && c == '$' && i+1 < n && src[i+1] == '{':
+			i = scanJSTemplateExpr(src, i+2)
+		case quote != '`' && c == '\n':
+			// Unterminated string; stop at the line break
+			return i
+		default:
+			i++
+		}
+	}
+	return n
+}
+
+// scanJSTemplateExpr returns the index just past the "}" closing a template
+// expression whose body starts at i, skipping nested literals and braces
+func scanJSTemplateExpr(src []byte, i int) int {
+	depth := 1
+	n := len(src)
+	for i < n {
+		switch c := src[i]; c {
+		case '\'', '"', '`':
+			i = scanJSLiteral(src, i)
+		case '{':
+			depth++
+			i++
+		case '}':
+			depth--
+			i++
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return n
+}
+
+// scanJSRegex returns the index just past the regex literal (including flags) starting at i
+func scanJSRegex(src []byte, i int) int {
+	n := len(src)
+	inClass := false
+	i++
+	for i < n {
+		switch c := src[i]; {
+		case c == '\\':
+			i += 2
+		case c == '[':
+			inClass = true
+			i++
+		case c == ']':
+			inClass = false
+			i++
+		case c == '/' && !inClass:
+			i++
+			for i < n && isJSWordByte(src[i]) {
+				i++
+			}
+			return i
+		case c == '\n':
+			return i
+		default:
+			i++
+		}
+	}
+	return n
+}
+
+// jsRegexAllowed reports whether a "/" after lastTok starts a regex literal rather than division
+func jsRegexAllowed(lastTok byte, lastWord string) bool {
+	if lastTok == 0 || strings.IndexByte("(,=:[!&|?{};+-*%<>~^", lastTok) >= 0 {
+		return true
+	}
+	switch lastWord {
+	case "return", "typeof", "case", "do", "else", "in", "of", "new", "delete", "void", "throw", "instanceof":
+		return true
+	}
+	return false
+}
+
+// jsNeedsSpace reports whether whitespace between prev and next must be kept
+func jsNeedsSpace(prev, next byte) bool {
+	switch {
+	case isJSWordByte(prev) && isJSWordByte(next):
+		return true
+	case (prev == '+' || prev == '-') && prev == next:
+		// "a + +b" must not become "a++b"
+		return true
+	case prev == '/' && (next == '/' || next == '*'):
+		// must not start a comment
+		return true
+	case prev >= '0' && prev <= '9' && next == '.':
+		// "1 .toString()" must not become "1.toString()"
+		return true
+	}
+	return false
+}
+
+// isJSWordByte reports whether c can be part of an identifier, keyword or number
+func isJSWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,58 @@
+// Command labelaudit checks locale JSON files against ui.CommonLabels and prints a
+// checklist of missing, empty, extra and mistyped keys per locale.
+//
+// Usage:
+//
+//	go run leapfor.xyz/pyeza-golang/cmd/labelaudit [-markdown] <locales-dir>
+//
+// Exits with status 1 when any issue is found.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	ui "leapfor.xyz/pyeza-golang"
+)
+
+func main() {
+	markdown := flag.Bool("markdown", false, "print a markdown checklist for translators")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: labelaudit [-markdown] <locales-dir>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	issues, err := ui.AuditLabels(os.DirFS(flag.Arg(0)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "labelaudit: %v\n", err)
+		os.Exit(2)
+	}
+
+	locale := ""
+	for _, issue := range issues {
+		if !*markdown {
+			fmt.Println(issue)
+			continue
+		}
+		if issue.Locale != locale {
+			if locale != "" {
+				fmt.Println()
+			}
+			locale = issue.Locale
+			fmt.Printf("## %s\n\n", locale)
+		}
+		fmt.Printf("- [ ] `%s` (%s)\n", issue.Key, issue.Problem)
+	}
+
+	if len(issues) > 0 {
+		fmt.Fprintf(os.Stderr, "labelaudit: %d issues\n", len(issues))
+		os.Exit(1)
+	}
+}
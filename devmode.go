@@ -0,0 +1,244 @@
+package ui
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DevReloadPath is the conventional path for the dev-mode reload event stream.
+// Mount it with: mux.Handle(ui.DevReloadPath, renderer.ReloadHandler())
+const DevReloadPath = "/__ui/reload"
+
+// devPollInterval is how often dev mode checks template sources for changes
+const devPollInterval = 500 * time.Millisecond
+
+// devWatcher holds dev-mode state: the reload URL, the stop signal
+// and the connected reload event subscribers
+type devWatcher struct {
+	reloadURL string
+	stop      chan struct{}
+	stopOnce  sync.Once
+
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+// fileStamp identifies a version of a template source file
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// WithDevMode enables hot reloading: after Init, the renderer polls the shared
+// component directories and the app's templatePatterns and re-parses all templates
+// when a file is added, removed or changed. If the new set fails to parse, the
+// error is logged and the last good set keeps serving.
+//
+// Shared components only change on disk when read from a directory
+// (see SetSourceDir / WithFS); the embedded copy never changes.
+//
+// reloadURL is where the "dev-reload" partial connects an EventSource to reload
+// open browsers after a successful re-parse. Pass "" to disable browser reloads,
+// or DevReloadPath and mount ReloadHandler there.
+//
+// Example:
+//
+//	renderer := ui.NewHTMLRenderer(patterns)
+//	if cfg.Dev {
+//	    renderer.WithDevMode(ui.DevReloadPath)
+//	    mux.Handle(ui.DevReloadPath, renderer.ReloadHandler())
+//	}
+func (r *HTMLRenderer) WithDevMode(reloadURL string) *HTMLRenderer {
+	r.dev = &devWatcher{
+		reloadURL:   reloadURL,
+		stop:        make(chan struct{}),
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+	return r
+}
+
+// Close stops the dev-mode watcher. It is a no-op outside dev mode.
+func (r *HTMLRenderer) Close() {
+	if r.dev == nil {
+		return
+	}
+	r.dev.stopOnce.Do(func() {
+		close(r.dev.stop)
+	})
+}
+
+// devReloadURL returns the reload event stream URL, or "" outside dev mode
+func (r *HTMLRenderer) devReloadURL() string {
+	if r.dev == nil {
+		return ""
+	}
+	return r.dev.reloadURL
+}
+
+// ReloadHandler returns a Server-Sent Events handler that sends a "reload" event
+// every time dev mode successfully re-parses the templates.
+// Outside dev mode it responds with 404.
+func (r *HTMLRenderer) ReloadHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if r.dev == nil {
+			http.NotFound(w, req)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming not supported", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+
+		ch := r.dev.subscribe()
+		defer r.dev.unsubscribe(ch)
+
+		fmt.Fprint(w, ": connected\n\n")
+		flusher.Flush()
+
+		for {
+			select {
+			case <-req.Context().Done():
+				return
+			case <-r.dev.stop:
+				return
+			case <-ch:
+				fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+				flusher.Flush()
+			}
+		}
+	})
+}
+
+// watch polls the template sources until Close is called
+func (r *HTMLRenderer) watch() {
+	last := r.snapshot()
+	ticker := time.NewTicker(devPollInterval)
+	defer ticker.Stop()
+
+	log.Printf("Dev mode: watching %d template files for changes", len(last))
+
+	for {
+		select {
+		case <-r.dev.stop:
+			return
+		case <-ticker.C:
+			current := r.snapshot()
+			if sameSnapshot(last, current) {
+				continue
+			}
+			last = current
+			r.reload()
+		}
+	}
+}
+
+// reload re-parses all templates, keeping the last good set on failure
+func (r *HTMLRenderer) reload() {
+	set, err := r.parse()
+	if err != nil {
+		log.Printf("Dev mode: template reload failed, keeping previous templates: %v", err)
+		return
+	}
+	if err := r.checkTemplates(set); err != nil {
+		log.Printf("Dev mode: template reload failed, keeping previous templates: %v", err)
+		return
+	}
+
+	r.mu.RLock()
+	previous := r.templates
+	r.mu.RUnlock()
+
+	r.setTemplates(set)
+
+	// Keep legacy handlers in sync if they were sharing this renderer's set
+	templates.CompareAndSwap(previous, set.templates)
+
+	log.Printf("Dev mode: templates reloaded")
+	r.dev.broadcast()
+}
+
+// snapshot stamps every file of the shared sources, the app template patterns and every page
+func (r *HTMLRenderer) snapshot() map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+
+	for _, source := range r.sharedSources() {
+		files, _ := source.files(r.sharedOptions.Exclude)
+		for _, file := range files {
+			if info, err := fs.Stat(source.FS, file); err == nil {
+				stamps["shared:"+source.Name+":"+file] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+			}
+		}
+	}
+
+	for _, pattern := range r.templatePatterns {
+		matches, _ := filepath.Glob(pattern)
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil {
+				stamps[match] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+			}
+		}
+	}
+
+	pageFiles, _ := r.pageFiles()
+	for _, file := range pageFiles {
+		if info, err := os.Stat(file); err == nil {
+			stamps[file] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+
+	return stamps
+}
+
+// sameSnapshot reports whether two snapshots describe the same files
+func sameSnapshot(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, stamp := range a {
+		other, ok := b[name]
+		if !ok || !other.modTime.Equal(stamp.modTime) || other.size != stamp.size {
+			return false
+		}
+	}
+	return true
+}
+
+// subscribe registers a reload event listener
+func (d *devWatcher) subscribe() chan struct{} {
+	ch := make(chan struct{}, 1)
+	d.mu.Lock()
+	d.subscribers[ch] = struct{}{}
+	d.mu.Unlock()
+	return ch
+}
+
+// unsubscribe removes a reload event listener
+func (d *devWatcher) unsubscribe(ch chan struct{}) {
+	d.mu.Lock()
+	delete(d.subscribers, ch)
+	d.mu.Unlock()
+}
+
+// broadcast notifies every subscriber without blocking on slow clients
+func (d *devWatcher) broadcast() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for ch := range d.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
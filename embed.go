@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// embeddedFS holds the shared templates, styles and scripts compiled into the binary.
+// Paths are relative to the package root (e.g., "components/table.html", "styles/table.css").
+//
+//go:embed components partials icons styles assets/js assets/vendor
+var embeddedFS embed.FS
+
+var (
+	sourceMu       sync.RWMutex
+	sourceOverride fs.FS
+)
+
+// SetSourceFS overrides the filesystem that shared templates and assets are read from.
+// The filesystem must use the same layout as the package root (components/, partials/,
+// icons/, styles/, assets/js/, assets/vendor/). Pass nil to go back to the embedded copy.
+//
+// Example (read straight from a checkout while developing the package):
+//
+//	ui.SetSourceFS(os.DirFS("../pyeza-golang"))
+func SetSourceFS(fsys fs.FS) {
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	sourceOverride = fsys
+}
+
+// SetSourceDir is shorthand for SetSourceFS(os.DirFS(dir)).
+// An empty dir restores the embedded copy.
+func SetSourceDir(dir string) {
+	if dir == "" {
+		SetSourceFS(nil)
+		return
+	}
+	SetSourceFS(os.DirFS(dir))
+}
+
+// SourceFS returns the filesystem shared templates and assets are read from.
+// Resolution order:
+// 1. The filesystem set via SetSourceFS / SetSourceDir
+// 2. The SHARED_COMPONENTS_DIR environment variable (read from disk)
+// 3. The copy embedded at build time
+func SourceFS() fs.FS {
+	sourceMu.RLock()
+	override := sourceOverride
+	sourceMu.RUnlock()
+
+	if override != nil {
+		return override
+	}
+	if envDir := os.Getenv("SHARED_COMPONENTS_DIR"); envDir != "" {
+		return os.DirFS(envDir)
+	}
+	return embeddedFS
+}
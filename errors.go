@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"sync"
+)
+
+// ErrorHandler writes the response for a failed render or a RenderError call.
+// err may be nil (e.g., a plain 404).
+type ErrorHandler func(w http.ResponseWriter, status int, err error)
+
+// ErrorPageData holds the data passed to the "error-page" template
+type ErrorPageData struct {
+	Status    int    // HTTP status code
+	Title     string // Error heading (from ErrorLabels by status)
+	Detail    string // Error details (only set in dev mode)
+	HomeURL   string // Link target for the back button
+	HomeLabel string // Back button text (button hidden when empty)
+}
+
+// bufferPool recycles render buffers so full pages are never half-written
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// getBuffer returns an empty buffer from the pool
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns a buffer to the pool, dropping oversized ones
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > 1<<20 {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// WithErrorHandler sets the handler called when rendering fails (status 500) and by
+// RenderError. Without an error handler, a failed Render writes nothing and only
+// returns the error.
+func (r *HTMLRenderer) WithErrorHandler(h ErrorHandler) *HTMLRenderer {
+	r.errorHandler = h
+	return r
+}
+
+// WithErrorPage sets an error handler that renders the themed "error-page" template,
+// titled from labels by status (NotFound, Unauthorized, Forbidden, General otherwise).
+// homeURL and homeLabel configure the back button (empty homeLabel hides it).
+// In dev mode the error message is shown on the page.
+//
+// Example:
+//
+//	renderer.WithErrorPage(labels.Errors, "/", labels.Buttons.Back)
+func (r *HTMLRenderer) WithErrorPage(labels ErrorLabels, homeURL, homeLabel string) *HTMLRenderer {
+	return r.WithErrorHandler(func(w http.ResponseWriter, status int, err error) {
+		data := ErrorPageData{
+			Status:    status,
+			Title:     labels.ForStatus(status),
+			HomeURL:   homeURL,
+			HomeLabel: homeLabel,
+		}
+		if r.dev != nil && err != nil {
+			data.Detail = err.Error()
+		}
+
+		buf := getBuffer()
+		defer putBuffer(buf)
+
+		if renderErr := r.execute(buf, "error-page", data); renderErr != nil {
+			// The error page itself failed; fall back to plain text
+			log.Printf("Error rendering error page: %v", renderErr)
+			http.Error(w, data.Title, status)
+			return
+		}
+		if writeErr := writeHTML(w, status, buf); writeErr != nil {
+			log.Printf("Warning: %v", writeErr)
+		}
+	})
+}
+
+// ForStatus returns the label for an HTTP status code, falling back to
+// http.StatusText when the matching label is empty
+func (l ErrorLabels) ForStatus(status int) string {
+	var label string
+	switch status {
+	case http.StatusNotFound:
+		label = l.NotFound
+	case http.StatusUnauthorized:
+		label = l.Unauthorized
+	case http.StatusForbidden:
+		label = l.Forbidden
+	default:
+		label = l.General
+	}
+	if label == "" {
+		label = http.StatusText(status)
+	}
+	return label
+}
+
+// RenderError writes an error response through the error handler, or a plain
+// http.Error if none is configured. err may be nil.
+func (r *HTMLRenderer) RenderError(w http.ResponseWriter, status int, err error) {
+	if r.errorHandler != nil {
+		r.errorHandler(w, status, err)
+		return
+	}
+	http.Error(w, http.StatusText(status), status)
+}
+
+// handleRenderError passes a render failure to the error handler, if any
+func (r *HTMLRenderer) handleRenderError(w http.ResponseWriter, err error) {
+	if r.errorHandler != nil {
+		r.errorHandler(w, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,83 @@
+package ui_test
+
+import (
+	"fmt"
+
+	ui "leapfor.xyz/pyeza-golang"
+)
+
+// navLabels is an app's own navigation labels, loaded from its locale files
+// next to CommonLabels (e.g., under a "nav" key)
+type navLabels struct {
+	Main struct {
+		Title     string `json:"title"`
+		Dashboard string `json:"dashboard"`
+	} `json:"main"`
+	Clients struct {
+		Title    string `json:"title"`
+		Active   string `json:"active"`
+		Prospect string `json:"prospect"`
+	} `json:"clients"`
+	Support struct {
+		Title      string `json:"title"`
+		HelpCenter string `json:"helpCenter"`
+	} `json:"support"`
+}
+
+// appNav builds the app's navigation tree from its labels. ActiveNav values are the
+// app keys ("clients"); ActiveSubNav values are "<app>-<item>" (e.g., "clients-active").
+func appNav(labels navLabels) ui.Nav {
+	return ui.Nav{
+		Label: "Main navigation",
+		Sections: []ui.NavSection{
+			{
+				Key:   "main",
+				Title: labels.Main.Title,
+				Items: []ui.NavItem{
+					{Key: "dashboard", Label: labels.Main.Dashboard, Href: "/", Icon: "dashboard"},
+				},
+			},
+			{
+				Key: "apps",
+				Items: []ui.NavItem{
+					{Key: "clients", Label: labels.Clients.Title, Href: "/clients", Icon: "clients", Match: []string{"client-detail"}, Items: []ui.NavItem{
+						{Key: "clients-active", Label: labels.Clients.Active, Href: "/clients?status=active"},
+						{Key: "clients-prospect", Label: labels.Clients.Prospect, Href: "/clients?status=prospect"},
+					}},
+				},
+			},
+			{
+				Key:         "support",
+				Title:       labels.Support.Title,
+				Collapsible: true,
+				Collapsed:   true,
+				Items: []ui.NavItem{
+					{Key: "help", Label: labels.Support.HelpCenter, Href: "/help", Icon: "help"},
+				},
+			},
+		},
+	}
+}
+
+func ExampleNav() {
+	var labels navLabels
+	labels.Main.Title = "Main"
+	labels.Main.Dashboard = "Dashboard"
+	labels.Clients.Title = "Clients"
+	labels.Clients.Active = "Active"
+	labels.Clients.Prospect = "Prospects"
+	labels.Support.Title = "Support"
+	labels.Support.HelpCenter = "Help Center"
+
+	nav := appNav(labels).ForPage(ui.PageData{ActiveNav: "clients", ActiveSubNav: "clients-prospect"})
+
+	item, _ := nav.ActiveItem()
+	fmt.Println(item.Label, item.Expanded)
+	for _, sub := range item.Items {
+		fmt.Println(" ", sub.Label, sub.Active)
+	}
+	// Output:
+	// Clients true
+	//   Active false
+	//   Prospects true
+}
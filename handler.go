@@ -0,0 +1,343 @@
+package ui
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// AssetPathPrefix is the URL prefix AssetHandler is expected to be mounted under.
+// The shared partials (table-scripts, page-end, sheet-form-container) reference
+// component assets below this prefix.
+const AssetPathPrefix = "/assets/components/"
+
+// assetURLRoot is the URL root logical asset names are resolved against.
+// A logical name such as "components/table/table.js" maps to "/assets/components/table/table.js".
+const assetURLRoot = "/assets/"
+
+// logicalAssetPrefix is the logical name prefix for assets served by AssetHandler
+const logicalAssetPrefix = "components/"
+
+// fingerprintLen is the number of hex characters of the content hash embedded in asset URLs
+const fingerprintLen = 12
+
+// fingerprintRe matches a fingerprinted asset name (e.g., "table/table.3f2a9b1c0d4e.js")
+var fingerprintRe = regexp.MustCompile(`^(.+)\.([0-9a-f]{12})(\.[^./]+)$`)
+
+// defaultAssets is the asset set used by AssetURL and the "asset" template function
+var defaultAssets atomic.Pointer[AssetHandler]
+
+// SetAssets sets the asset set used by AssetURL and the "asset" template function.
+// NewAssetHandler calls this automatically; call it only to switch between handlers.
+func SetAssets(h *AssetHandler) {
+	defaultAssets.Store(h)
+}
+
+// AssetURL resolves a logical asset name to its URL.
+// Package assets (names starting with "components/") resolve to a content-hashed URL
+// that can be cached immutably, e.g.
+//
+//	"components/table/table.js" -> "/assets/components/table/table.3f2a9b1c0d4e.js"
+//
+// Names the asset handler doesn't know (or when no handler has been created)
+// resolve to the plain "/assets/" URL.
+func AssetURL(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	if h := defaultAssets.Load(); h != nil {
+		if url, ok := h.URL(name); ok {
+			return url
+		}
+	}
+	return assetURLRoot + name
+}
+
+// AssetIntegrity returns the Subresource Integrity value ("sha384-...") of a package asset,
+// for the integrity attribute of <script> and <link> tags. Returns "" for names the asset
+// handler doesn't know (or when no handler has been created).
+//
+// Example (template):
+//
+//	<script src="{{asset "components/sheet.js"}}" integrity="{{assetIntegrity "components/sheet.js"}}"></script>
+func AssetIntegrity(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	if h := defaultAssets.Load(); h != nil {
+		if integrity, ok := h.Integrity(name); ok {
+			return integrity
+		}
+	}
+	return ""
+}
+
+// assetFile is a single in-memory asset served by AssetHandler
+type assetFile struct {
+	name        string // URL path relative to AssetPathPrefix (e.g., "table/table-core.js")
+	data        []byte
+	contentType string
+	hash        string // hex SHA-256 of data
+	integrity   string // Subresource Integrity value (sha384, base64)
+}
+
+// fingerprintedName returns the name with the content hash inserted before the extension
+// (e.g., "table/table.js" -> "table/table.3f2a9b1c0d4e.js")
+func (f *assetFile) fingerprintedName() string {
+	ext := path.Ext(f.name)
+	return strings.TrimSuffix(f.name, ext) + "." + f.hash[:fingerprintLen] + ext
+}
+
+// etag returns the strong ETag for the asset
+func (f *assetFile) etag() string {
+	return `"` + f.hash[:16] + `"`
+}
+
+// AssetHandler serves the package's component assets straight from memory.
+// It replaces the CopyStaticAssets / CopyStylesWithTheme copy step.
+//
+// URL layout (relative to AssetPathPrefix):
+//   - table/*.js            Table JS modules
+//   - table/table.bundle.js Minified bundle of the table modules (+ .map source map)
+//   - sheet.js, dialog.js, help-pane.js, message.js, sidebar.js
+//   - vendor/htmx-<release>.min.js Vendored HTMX builds (see SetHTMXVersion)
+//   - css/<component>.css   Component styles
+//   - css/main.css          Generated main.css for the configured theme and font
+type AssetHandler struct {
+	files   map[string]*assetFile
+	modTime time.Time
+}
+
+// NewAssetHandler builds the in-memory asset set from SourceFS(), generating main.css
+// with the given default theme and font (same options as CopyStylesWithTheme).
+// The handler becomes the default asset set for AssetURL and the "asset" template function.
+// It fails if a listed component script or vendored file (e.g., the HTMX builds) is missing,
+// rather than serving pages whose scripts 404.
+//
+// Example:
+//
+//	assets, err := ui.NewAssetHandler("warm-cream", "default")
+//	if err != nil {
+//	    log.Fatalf("Failed to load component assets: %v", err)
+//	}
+//	mux.Handle(ui.AssetPathPrefix, assets)
+func NewAssetHandler(theme, font string) (*AssetHandler, error) {
+	files, err := buildAssetFiles(SourceFS(), theme, font)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &AssetHandler{
+		files:   files,
+		modTime: time.Now(),
+	}
+	SetAssets(h)
+
+	log.Printf("Loaded %d component assets for serving under: %s", len(files), AssetPathPrefix)
+	return h, nil
+}
+
+// URL returns the fingerprinted URL for a logical asset name (e.g., "components/css/main.css").
+// Returns false if the name is not one of this handler's assets.
+func (h *AssetHandler) URL(name string) (string, bool) {
+	rel, ok := strings.CutPrefix(name, logicalAssetPrefix)
+	if !ok {
+		return "", false
+	}
+	file, ok := h.files[rel]
+	if !ok {
+		return "", false
+	}
+	return AssetPathPrefix + file.fingerprintedName(), true
+}
+
+// Integrity returns the Subresource Integrity value of a logical asset name
+// (e.g., "components/vendor/htmx-1.9.12.min.js" -> "sha384-...").
+// Returns false if the name is not one of this handler's assets.
+func (h *AssetHandler) Integrity(name string) (string, bool) {
+	rel, ok := strings.CutPrefix(name, logicalAssetPrefix)
+	if !ok {
+		return "", false
+	}
+	file, ok := h.files[rel]
+	if !ok {
+		return "", false
+	}
+	return file.integrity, true
+}
+
+// Manifest returns the mapping from logical asset names to fingerprinted URLs, e.g.
+//
+//	"components/table/table.js" -> "/assets/components/table/table.3f2a9b1c0d4e.js"
+//
+// Useful for preload headers or handing asset URLs to client-side code.
+func (h *AssetHandler) Manifest() map[string]string {
+	manifest := make(map[string]string, len(h.files))
+	for name, file := range h.files {
+		manifest[logicalAssetPrefix+name] = AssetPathPrefix + file.fingerprintedName()
+	}
+	return manifest
+}
+
+// lookup finds the asset for a request path, resolving fingerprinted names.
+// fingerprinted reports whether the request used the current content hash.
+func (h *AssetHandler) lookup(name string) (file *assetFile, fingerprinted bool) {
+	if file, ok := h.files[name]; ok {
+		return file, false
+	}
+
+	m := fingerprintRe.FindStringSubmatch(name)
+	if m == nil {
+		return nil, false
+	}
+	file, ok := h.files[m[1]+m[3]]
+	if !ok {
+		return nil, false
+	}
+	// A stale hash (e.g., an old page after a deploy) still gets the current
+	// content, but must not be cached as immutable.
+	return file, strings.HasPrefix(file.hash, m[2])
+}
+
+// ServeHTTP serves a single asset with Content-Type, ETag and Cache-Control headers.
+// Works both when mounted directly at AssetPathPrefix and behind http.StripPrefix.
+func (h *AssetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, AssetPathPrefix)
+	name = strings.TrimPrefix(name, "/")
+
+	file, fingerprinted := h.lookup(name)
+	if file == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", file.contentType)
+	w.Header().Set("ETag", file.etag())
+	if fingerprinted {
+		// Content-hashed URLs never change content, so browsers can keep them forever
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		// Unversioned URLs must revalidate; the ETag makes that a cheap 304.
+		w.Header().Set("Cache-Control", "public, no-cache")
+	}
+
+	// ServeContent handles If-None-Match, Range and HEAD requests
+	http.ServeContent(w, r, name, h.modTime, bytes.NewReader(file.data))
+}
+
+// buildAssetFiles collects every servable asset from srcFS, keyed by URL path
+// relative to AssetPathPrefix.
+func buildAssetFiles(srcFS fs.FS, theme, font string) (map[string]*assetFile, error) {
+	files := make(map[string]*assetFile)
+
+	// Component JS (same layout as CopyStaticAssets)
+	for _, asset := range staticAssets {
+		info, err := statStaticAsset(srcFS, asset)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			if err := addAssetFile(files, srcFS, asset.srcRelPath, asset.dstRelPath); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		scripts, err := fs.Glob(srcFS, path.Join(asset.srcRelPath, "*.js"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", asset.srcRelPath, err)
+		}
+		for _, src := range scripts {
+			if err := addAssetFile(files, srcFS, src, path.Join(asset.dstRelPath, path.Base(src))); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Minified table bundle (used by table-scripts in production mode)
+	bundle, err := bundleScripts(srcFS, "assets/js/table", tableScriptOrder, tableBundleName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bundle table scripts: %w", err)
+	}
+	files[tableBundleName] = newAssetFile(tableBundleName, bundle.js)
+	files[tableBundleName+".map"] = newAssetFile(tableBundleName+".map", bundle.sourceMap)
+
+	// Icon sprite sheet for <use href> rendering (see Icon)
+	files[iconSpriteName] = newAssetFile(iconSpriteName, buildIconSprite(false))
+
+	// Component CSS (same filter as CopyStylesWithTheme)
+	styles, err := fs.Glob(srcFS, path.Join("styles", "*.css"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list styles: %w", err)
+	}
+	for _, src := range styles {
+		if excludedStyles[path.Base(src)] {
+			continue
+		}
+		if err := addAssetFile(files, srcFS, src, path.Join("css", path.Base(src))); err != nil {
+			return nil, err
+		}
+	}
+
+	// Generated main.css
+	mainCSS, _, err := buildMainCSS(srcFS, theme, font)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate main.css: %w", err)
+	}
+	files["css/main.css"] = newAssetFile("css/main.css", mainCSS)
+
+	return files, nil
+}
+
+// addAssetFile reads src from srcFS and registers it under name
+func addAssetFile(files map[string]*assetFile, srcFS fs.FS, src, name string) error {
+	data, err := fs.ReadFile(srcFS, src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	files[name] = newAssetFile(name, data)
+	return nil
+}
+
+// newAssetFile creates an assetFile, computing its content type, hash and integrity value
+func newAssetFile(name string, data []byte) *assetFile {
+	sum := sha256.Sum256(data)
+	sri := sha512.Sum384(data)
+	return &assetFile{
+		name:        name,
+		data:        data,
+		contentType: assetContentType(name),
+		hash:        hex.EncodeToString(sum[:]),
+		integrity:   "sha384-" + base64.StdEncoding.EncodeToString(sri[:]),
+	}
+}
+
+// assetContentType returns the Content-Type for an asset based on its extension
+func assetContentType(name string) string {
+	switch path.Ext(name) {
+	case ".js":
+		return "text/javascript; charset=utf-8"
+	case ".css":
+		return "text/css; charset=utf-8"
+	case ".map", ".json":
+		return "application/json; charset=utf-8"
+	case ".svg":
+		return "image/svg+xml"
+	default:
+		return "application/octet-stream"
+	}
+}
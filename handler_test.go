@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base64"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewAssetHandlerServesHTMX(t *testing.T) {
+	h, err := NewAssetHandler("warm-cream", "default")
+	if err != nil {
+		t.Fatalf("NewAssetHandler: %v", err)
+	}
+	defer SetAssets(nil)
+
+	for _, version := range []HTMXVersion{HTMX1, HTMX2} {
+		name := htmxAssets[version]
+		t.Run(name, func(t *testing.T) {
+			url, ok := h.URL(name)
+			if !ok {
+				t.Fatalf("URL(%q): not an asset", name)
+			}
+			integrity, ok := h.Integrity(name)
+			if !ok || !strings.HasPrefix(integrity, "sha384-") {
+				t.Fatalf("Integrity(%q) = %q, %v, want a sha384 value", name, integrity, ok)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", url, nil))
+			if rec.Code != 200 {
+				t.Fatalf("GET %s: status %d", url, rec.Code)
+			}
+			if ct := rec.Header().Get("Content-Type"); !strings.Contains(ct, "javascript") {
+				t.Errorf("Content-Type = %q, want JavaScript", ct)
+			}
+			body, _ := io.ReadAll(rec.Body)
+			if !bytes.Contains(body, []byte("htmx")) {
+				t.Errorf("GET %s: body is not an htmx build (%d bytes)", url, len(body))
+			}
+			sum := sha512.Sum384(body)
+			if want := "sha384-" + base64.StdEncoding.EncodeToString(sum[:]); integrity != want {
+				t.Errorf("Integrity = %q, want %q for the served body", integrity, want)
+			}
+		})
+	}
+}
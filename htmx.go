@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// IsHTMX reports whether the request was made by HTMX (HX-Request header)
+func IsHTMX(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true"
+}
+
+// IsBoosted reports whether the request came from an hx-boost link or form.
+// Boosted requests swap the whole <body>, so they expect a full page.
+func IsBoosted(r *http.Request) bool {
+	return r.Header.Get("HX-Boosted") == "true"
+}
+
+// HTMXResponse builds a response that adapts to the request: a full page for normal
+// navigation, or the main fragment plus any out-of-band fragments for HTMX requests.
+// Create one with HTMLRenderer.Respond and finish with Write.
+//
+// Example:
+//
+//	return renderer.Respond(r).
+//	    Page("clients/list", data).
+//	    Fragment("table-body-partial", data.Table).
+//	    OOB("header-oob", data).
+//	    Toast("success", "Client saved.").
+//	    Trigger("clientsChanged", nil).
+//	    Write(w)
+type HTMXResponse struct {
+	renderer *HTMLRenderer
+	req      *http.Request
+
+	page     *htmxFragment // rendered for normal (non-HTMX) and boosted requests
+	fragment *htmxFragment // rendered for HTMX requests
+	oob      []htmxFragment
+
+	triggers map[string]any
+	headers  http.Header
+	status   int
+}
+
+// htmxFragment is a template name and the data to render it with
+type htmxFragment struct {
+	name string
+	data any
+}
+
+// Respond starts an HTMX-aware response for the request
+func (r *HTMLRenderer) Respond(req *http.Request) *HTMXResponse {
+	return &HTMXResponse{
+		renderer: r,
+		req:      req,
+		headers:  make(http.Header),
+		status:   http.StatusOK,
+	}
+}
+
+// Page sets the template rendered for normal and boosted requests (e.g., "clients/list")
+func (resp *HTMXResponse) Page(name string, data any) *HTMXResponse {
+	resp.page = &htmxFragment{name: name, data: data}
+	return resp
+}
+
+// Fragment sets the main template rendered for HTMX requests (e.g., "table-body-partial").
+// If no Page is set, the fragment is also rendered for normal requests.
+func (resp *HTMXResponse) Fragment(name string, data any) *HTMXResponse {
+	resp.fragment = &htmxFragment{name: name, data: data}
+	return resp
+}
+
+// OOB appends an out-of-band fragment (e.g., "header-oob", "help-pane-oob").
+// The template must carry its own hx-swap-oob attribute. OOB fragments are only
+// rendered for HTMX requests; a full page already contains them.
+func (resp *HTMXResponse) OOB(name string, data any) *HTMXResponse {
+	resp.oob = append(resp.oob, htmxFragment{name: name, data: data})
+	return resp
+}
+
+// Toast appends a "toast-oob" fragment with the given state (success, error, warning, info)
+func (resp *HTMXResponse) Toast(state, message string) *HTMXResponse {
+	return resp.OOB("toast-oob", NewToastProps(state, message))
+}
+
+// Trigger adds a client-side event to the HX-Trigger header.
+// detail is marshalled to JSON and passed as event.detail (nil for no detail).
+func (resp *HTMXResponse) Trigger(event string, detail any) *HTMXResponse {
+	if resp.triggers == nil {
+		resp.triggers = make(map[string]any)
+	}
+	resp.triggers[event] = detail
+	return resp
+}
+
+// PushURL sets HX-Push-Url so HTMX pushes url into the browser history
+func (resp *HTMXResponse) PushURL(url string) *HTMXResponse {
+	resp.headers.Set("HX-Push-Url", url)
+	return resp
+}
+
+// ReplaceURL sets HX-Replace-Url so HTMX replaces the current history entry with url
+func (resp *HTMXResponse) ReplaceURL(url string) *HTMXResponse {
+	resp.headers.Set("HX-Replace-Url", url)
+	return resp
+}
+
+// Retarget sets HX-Retarget to swap the response into a different element (CSS selector)
+func (resp *HTMXResponse) Retarget(selector string) *HTMXResponse {
+	resp.headers.Set("HX-Retarget", selector)
+	return resp
+}
+
+// Reswap sets HX-Reswap to override the swap strategy (e.g., "outerHTML", "beforeend")
+func (resp *HTMXResponse) Reswap(swap string) *HTMXResponse {
+	resp.headers.Set("HX-Reswap", swap)
+	return resp
+}
+
+// Status sets the response status code (default 200)
+func (resp *HTMXResponse) Status(code int) *HTMXResponse {
+	resp.status = code
+	return resp
+}
+
+// Write renders the response and writes it with its headers. Templates are rendered
+// with the request's request-scoped funcs bound (see RenderRequest).
+// If any template fails, nothing is written and the error goes to the renderer's
+// error handler (see WithErrorHandler).
+func (resp *HTMXResponse) Write(w http.ResponseWriter) error {
+	htmx := IsHTMX(resp.req) && !IsBoosted(resp.req)
+
+	var parts []htmxFragment
+	switch {
+	case htmx && resp.fragment != nil:
+		parts = append(parts, *resp.fragment)
+		parts = append(parts, resp.oob...)
+	case resp.page != nil:
+		parts = append(parts, *resp.page)
+	case resp.fragment != nil:
+		parts = append(parts, *resp.fragment)
+	default:
+		return fmt.Errorf("htmx response has neither a page nor a fragment")
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	for _, part := range parts {
+		if err := resp.renderer.executeRequest(buf, resp.req, part.name, part.data); err != nil {
+			resp.renderer.handleRenderError(w, err)
+			return err
+		}
+	}
+
+	header := w.Header()
+	for key, values := range resp.headers {
+		header[key] = values
+	}
+	if len(resp.triggers) > 0 {
+		triggers, err := json.Marshal(resp.triggers)
+		if err != nil {
+			return fmt.Errorf("failed to encode HX-Trigger: %w", err)
+		}
+		header.Set("HX-Trigger", string(triggers))
+	}
+	// Responses differ by HX-Request, so caches must keep them apart
+	header.Add("Vary", "HX-Request")
+	return writeHTML(w, resp.status, buf)
+}
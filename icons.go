@@ -0,0 +1,310 @@
+package ui
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// iconSpriteName is the sprite sheet's name relative to AssetPathPrefix
+const iconSpriteName = "icons/sprite.svg"
+
+// iconDefineRe matches an icon template: {{define "icon-name"}}<svg ...>...</svg>{{end}}
+var iconDefineRe = regexp.MustCompile(`(?s)\{\{-?\s*define\s+"icon-([^"]+)"\s*-?\}\}\s*(<svg.*?</svg>)\s*\{\{-?\s*end\s*-?\}\}`)
+
+// iconSVGRe splits an SVG element into its attributes and inner markup
+var iconSVGRe = regexp.MustCompile(`(?s)^\s*<svg\b([^>]*)>(.*)</svg>\s*$`)
+
+// iconSizeAttrRe matches the attributes the icon function sets itself
+var iconSizeAttrRe = regexp.MustCompile(`\s(width|height|class|xmlns)="[^"]*"`)
+
+// iconDef is a parsed SVG icon
+type iconDef struct {
+	attrs string // <svg> attributes except width, height, class and xmlns (e.g., viewBox, stroke)
+	body  string // inner markup
+}
+
+var (
+	iconMu       sync.RWMutex
+	builtinIcons map[string]iconDef // the shared icons/ templates, loaded on first use
+	appIcons     = map[string]iconDef{}
+)
+
+// RegisterIcon adds an icon (or replaces one with the same name) for the "icon"
+// template function, the sprite sheet and the renderer's "icon-<name>" templates.
+// name is given without the "icon-" prefix; svg is a complete <svg> element.
+// Register icons before NewAssetHandler and HTMLRenderer.Init so they are included.
+//
+// Example:
+//
+//	ui.RegisterIcon("invoice", `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor">...</svg>`)
+func RegisterIcon(name, svg string) error {
+	icon, err := parseIconSVG(svg)
+	if err != nil {
+		return fmt.Errorf("failed to register icon %s: %w", name, err)
+	}
+
+	iconMu.Lock()
+	appIcons[strings.TrimPrefix(name, "icon-")] = icon
+	iconMu.Unlock()
+	return nil
+}
+
+// RegisterIconsFS registers every icon matched by pattern in fsys.
+// .svg files are named after the file (e.g., "invoice.svg" is "invoice");
+// .html files may define any number of {{define "icon-<name>"}} templates.
+func RegisterIconsFS(fsys fs.FS, pattern string) error {
+	matches, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return fmt.Errorf("invalid icon pattern %s: %w", pattern, err)
+	}
+
+	for _, match := range matches {
+		content, err := fs.ReadFile(fsys, match)
+		if err != nil {
+			return fmt.Errorf("failed to read icon %s: %w", match, err)
+		}
+
+		switch path.Ext(match) {
+		case ".svg":
+			name := strings.TrimSuffix(path.Base(match), ".svg")
+			if err := RegisterIcon(name, string(content)); err != nil {
+				return err
+			}
+		case ".html":
+			for _, m := range iconDefineRe.FindAllStringSubmatch(string(content), -1) {
+				if err := RegisterIcon(m[1], m[2]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// IconNames returns the names of all available icons (built-in and registered), sorted
+func IconNames() []string {
+	icons := allIcons()
+	names := make([]string, 0, len(icons))
+	for name := range icons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Icon renders an icon as inline SVG. name may include the "icon-" prefix.
+// options are key/value pairs:
+//
+//	"size"   - width and height: a number of pixels (20) or a CSS length ("1.25rem")
+//	"class"  - extra CSS classes (the element always has class "icon")
+//	"label"  - accessible name; icons without a label are aria-hidden
+//	"sprite" - true to render <use href> into the sprite sheet instead of the paths
+//
+// Template usage:
+//
+//	{{icon "trash-2"}}
+//	{{icon "trash-2" "size" 16 "class" "text-danger" "label" "Delete"}}
+//	{{icon "plus" "sprite" true}}
+func Icon(name string, options ...any) template.HTML {
+	name = strings.TrimPrefix(name, "icon-")
+
+	icon, ok := allIcons()[name]
+	if !ok {
+		log.Printf("Warning: Icon not found: %s", name)
+		return ""
+	}
+
+	var size, class, label string
+	sprite := false
+	for i := 0; i+1 < len(options); i += 2 {
+		key, _ := options[i].(string)
+		switch value := options[i+1]; key {
+		case "size":
+			size = iconOptionString(value)
+		case "class":
+			class = iconOptionString(value)
+		case "label":
+			label = iconOptionString(value)
+		case "sprite":
+			sprite, _ = value.(bool)
+		default:
+			log.Printf("Warning: Unknown icon option %q for icon %s", key, name)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg"`)
+	b.WriteString(icon.attrs)
+	b.WriteString(` class="icon`)
+	if class != "" {
+		b.WriteString(" " + template.HTMLEscapeString(class))
+	}
+	b.WriteString(`"`)
+	if size != "" {
+		size = template.HTMLEscapeString(size)
+		b.WriteString(` width="` + size + `" height="` + size + `"`)
+	}
+	if label != "" {
+		b.WriteString(` role="img" aria-label="` + template.HTMLEscapeString(label) + `"`)
+	} else {
+		b.WriteString(` aria-hidden="true" focusable="false"`)
+	}
+	b.WriteString(">")
+	if sprite {
+		b.WriteString(`<use href="` + template.HTMLEscapeString(iconSpriteHref(name)) + `"/>`)
+	} else {
+		b.WriteString(icon.body)
+	}
+	b.WriteString("</svg>")
+
+	return template.HTML(b.String())
+}
+
+// InlineIconSprite returns a hidden sprite sheet of every icon, for pages that
+// render icons with "sprite" true but don't serve the sprite through AssetHandler.
+// Place it once at the start of <body>: {{iconSprite}}
+func InlineIconSprite() template.HTML {
+	return template.HTML(buildIconSprite(true))
+}
+
+// iconSpriteHref returns the <use href> of an icon: the sprite sheet served by the
+// asset handler when there is one, otherwise the inline sprite (see InlineIconSprite)
+func iconSpriteHref(name string) string {
+	if h := defaultAssets.Load(); h != nil {
+		if url, ok := h.URL(logicalAssetPrefix + iconSpriteName); ok {
+			return url + "#icon-" + name
+		}
+	}
+	return "#icon-" + name
+}
+
+// buildIconSprite renders every icon as a <symbol id="icon-<name>">
+func buildIconSprite(inline bool) []byte {
+	icons := allIcons()
+
+	var b strings.Builder
+	if inline {
+		b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" style="display:none" aria-hidden="true">`)
+	} else {
+		b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg">`)
+	}
+	b.WriteString("\n")
+	for _, name := range IconNames() {
+		icon := icons[name]
+		b.WriteString(`<symbol id="icon-` + name + `"` + icon.attrs + ">")
+		b.WriteString(strings.TrimSpace(icon.body))
+		b.WriteString("</symbol>\n")
+	}
+	b.WriteString("</svg>\n")
+	return []byte(b.String())
+}
+
+// addIconTemplates defines an "icon-<name>" template for every registered icon the
+// set doesn't already define, so app icons work with {{template "icon-<name>"}}
+func addIconTemplates(tmpl *template.Template) error {
+	iconMu.RLock()
+	defer iconMu.RUnlock()
+
+	for name, icon := range appIcons {
+		templateName := "icon-" + name
+		if tmpl.Lookup(templateName) != nil {
+			continue
+		}
+		svg := "<svg" + icon.attrs + ">" + icon.body + "</svg>"
+		if _, err := tmpl.New(templateName).Parse(svg); err != nil {
+			return fmt.Errorf("failed to add icon template %s: %w", templateName, err)
+		}
+	}
+	return nil
+}
+
+// allIcons returns the built-in icons overlaid with the registered ones
+func allIcons() map[string]iconDef {
+	loadBuiltinIcons()
+
+	iconMu.RLock()
+	defer iconMu.RUnlock()
+
+	icons := make(map[string]iconDef, len(builtinIcons)+len(appIcons))
+	for name, icon := range builtinIcons {
+		icons[name] = icon
+	}
+	for name, icon := range appIcons {
+		icons[name] = icon
+	}
+	return icons
+}
+
+// loadBuiltinIcons parses the shared icons/ templates from SourceFS once
+func loadBuiltinIcons() {
+	iconMu.RLock()
+	loaded := builtinIcons != nil
+	iconMu.RUnlock()
+	if loaded {
+		return
+	}
+
+	icons := make(map[string]iconDef)
+	srcFS := SourceFS()
+	files, err := fs.Glob(srcFS, "icons/*.html")
+	if err != nil {
+		log.Printf("Warning: Failed to list icons: %v", err)
+	}
+	for _, file := range files {
+		content, err := fs.ReadFile(srcFS, file)
+		if err != nil {
+			log.Printf("Warning: Failed to read icon %s: %v", file, err)
+			continue
+		}
+		for _, m := range iconDefineRe.FindAllStringSubmatch(string(content), -1) {
+			icon, err := parseIconSVG(m[2])
+			if err != nil {
+				log.Printf("Warning: Skipping icon %s: %v", m[1], err)
+				continue
+			}
+			icons[m[1]] = icon
+		}
+	}
+
+	iconMu.Lock()
+	if builtinIcons == nil {
+		builtinIcons = icons
+	}
+	iconMu.Unlock()
+}
+
+// parseIconSVG splits an <svg> element into attributes and body
+func parseIconSVG(svg string) (iconDef, error) {
+	m := iconSVGRe.FindStringSubmatch(svg)
+	if m == nil {
+		return iconDef{}, fmt.Errorf("not an <svg> element")
+	}
+	attrs := iconSizeAttrRe.ReplaceAllString(m[1], "")
+	attrs = strings.TrimRight(attrs, " /")
+	if attrs != "" && !strings.HasPrefix(attrs, " ") {
+		attrs = " " + attrs
+	}
+	return iconDef{attrs: attrs, body: m[2]}, nil
+}
+
+// iconOptionString formats an icon option value
+func iconOptionString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
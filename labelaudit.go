@@ -0,0 +1,269 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Label audit problems
+const (
+	LabelMissing = "missing" // the struct has the key, the locale (merged over its base language) doesn't
+	LabelEmpty   = "empty"   // the key is an empty string (renders blank)
+	LabelExtra   = "extra"   // the locale file has a key the struct doesn't know
+	LabelInvalid = "invalid" // the value has the wrong type (e.g., an object for a string)
+)
+
+// LabelIssue is a problem found by AuditLabels in one locale file
+type LabelIssue struct {
+	Locale  string // locale file name without .json (e.g., "fr")
+	Key     string // dotted JSON path (e.g., "bulk.approve")
+	Problem string // LabelMissing, LabelEmpty, LabelExtra or LabelInvalid
+}
+
+func (i LabelIssue) String() string {
+	return fmt.Sprintf("%s: %s (%s)", i.Locale, i.Key, i.Problem)
+}
+
+// TestingT is the subset of testing.TB used by CheckLabels
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Problems checked on a locale file as written, and on the locale as LabelLoader merges it
+var (
+	labelFileProblems   = map[string]bool{LabelExtra: true, LabelInvalid: true}
+	labelMergedProblems = map[string]bool{LabelMissing: true, LabelEmpty: true}
+)
+
+// AuditLabels checks every locale file in fsys (see LabelLoader for the layout) against
+// CommonLabels and reports missing, empty, extra and mistyped keys, sorted by locale and key.
+// Extra and mistyped keys are checked in each file as written. Missing and empty keys are
+// checked after merging a regional locale over its base language, as LabelLoader does, so
+// en-AU.json only needs the keys that differ from en.json. Fallback to the default locale
+// is not applied: a key missing from fr.json is reported even though en.json fills it in.
+func AuditLabels(fsys fs.FS) ([]LabelIssue, error) {
+	return AuditLabelsFor(fsys, CommonLabels{})
+}
+
+// AuditLabelsFor is AuditLabels for an app's own labels struct (or pointer to one)
+func AuditLabelsFor(fsys fs.FS, labels any) ([]LabelIssue, error) {
+	t, err := labelStructType(labels)
+	if err != nil {
+		return nil, err
+	}
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list locale files: %w", err)
+	}
+	sort.Strings(files)
+
+	locales := make([]string, 0, len(files))
+	values := make(map[string]map[string]any, len(files))
+	for _, file := range files {
+		locale := strings.TrimSuffix(file, ".json")
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read locale %s: %w", file, err)
+		}
+		if values[locale], err = parseLabelFile(locale, content); err != nil {
+			return nil, err
+		}
+		locales = append(locales, locale)
+	}
+
+	var issues []LabelIssue
+	for _, locale := range locales {
+		merged := values[locale]
+		if base := findLocale(locales, baseLanguage(locale)); base != "" && base != locale {
+			merged = map[string]any{}
+			mergeLabelValues(merged, values[base])
+			mergeLabelValues(merged, values[locale])
+		}
+
+		a := &labelAuditor{locale: locale}
+		a.audit(t, values[locale], labelFileProblems)
+		a.audit(t, merged, labelMergedProblems)
+		issues = append(issues, a.sorted()...)
+	}
+	return issues, nil
+}
+
+// AuditLabelFile checks a single locale file's content against a labels struct,
+// as written (without merging over its base language)
+func AuditLabelFile(locale string, content []byte, labels any) ([]LabelIssue, error) {
+	t, err := labelStructType(labels)
+	if err != nil {
+		return nil, err
+	}
+	values, err := parseLabelFile(locale, content)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &labelAuditor{locale: locale}
+	a.audit(t, values, nil)
+	return a.sorted(), nil
+}
+
+// parseLabelFile decodes a locale file's JSON object
+func parseLabelFile(locale string, content []byte) (map[string]any, error) {
+	var values map[string]any
+	if err := json.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse locale %s: %w", locale, err)
+	}
+	return values, nil
+}
+
+// labelStructType returns the struct type of a labels struct or pointer to one
+func labelStructType(labels any) (reflect.Type, error) {
+	t := reflect.TypeOf(labels)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("labels must be a struct, got %T", labels)
+	}
+	return t, nil
+}
+
+// CheckLabels fails t for every issue AuditLabels finds in fsys.
+//
+// Example (labels_test.go):
+//
+//	func TestLabels(t *testing.T) {
+//	    ui.CheckLabels(t, os.DirFS("locales"))
+//	}
+func CheckLabels(t TestingT, fsys fs.FS) {
+	t.Helper()
+	issues, err := AuditLabels(fsys)
+	if err != nil {
+		t.Errorf("label audit failed: %v", err)
+		return
+	}
+	for _, issue := range issues {
+		t.Errorf("label %s", issue)
+	}
+}
+
+// labelAuditor collects the issues of one locale
+type labelAuditor struct {
+	locale   string
+	problems map[string]bool // problems reported by the current audit (nil = all)
+	issues   []LabelIssue
+}
+
+// audit walks values against a struct type, reporting only the given problems (nil = all)
+func (a *labelAuditor) audit(t reflect.Type, values map[string]any, problems map[string]bool) {
+	a.problems = problems
+	a.walk(t, values, "")
+}
+
+// sorted returns the issues sorted by key
+func (a *labelAuditor) sorted() []LabelIssue {
+	sort.SliceStable(a.issues, func(i, j int) bool {
+		return a.issues[i].Key < a.issues[j].Key
+	})
+	return a.issues
+}
+
+// walk compares a struct type with the JSON object decoded for it. Keys match fields
+// case-insensitively, as json.Unmarshal does, preferring an exact match.
+func (a *labelAuditor) walk(t reflect.Type, values map[string]any, prefix string) {
+	known := make(map[string]bool, len(values))
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := labelKey(field)
+		if name == "" {
+			continue
+		}
+		key := prefix + name
+
+		jsonKey, ok := lookupLabelKey(values, name)
+		if ok {
+			known[jsonKey] = true
+		}
+		value := values[jsonKey]
+		if !ok {
+			a.reportMissing(field.Type, key)
+			continue
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			nested, ok := value.(map[string]any)
+			if !ok {
+				a.report(key, LabelInvalid)
+				continue
+			}
+			a.walk(field.Type, nested, key+".")
+		case reflect.String:
+			s, ok := value.(string)
+			switch {
+			case !ok:
+				a.report(key, LabelInvalid)
+			case strings.TrimSpace(s) == "":
+				a.report(key, LabelEmpty)
+			}
+		}
+	}
+
+	for name := range values {
+		if !known[name] {
+			a.report(prefix+name, LabelExtra)
+		}
+	}
+}
+
+// lookupLabelKey returns the key of values json.Unmarshal would decode into the
+// field named name: the exact key, else one equal under case folding
+func lookupLabelKey(values map[string]any, name string) (string, bool) {
+	if _, ok := values[name]; ok {
+		return name, true
+	}
+	for key := range values {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// reportMissing reports a missing key; a missing object reports each of its keys
+func (a *labelAuditor) reportMissing(t reflect.Type, key string) {
+	if t.Kind() != reflect.Struct {
+		a.report(key, LabelMissing)
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if name := labelKey(t.Field(i)); name != "" {
+			a.reportMissing(t.Field(i).Type, key+"."+name)
+		}
+	}
+}
+
+func (a *labelAuditor) report(key, problem string) {
+	if a.problems != nil && !a.problems[problem] {
+		return
+	}
+	a.issues = append(a.issues, LabelIssue{Locale: a.locale, Key: key, Problem: problem})
+}
+
+// labelKey returns a field's JSON key ("" for unexported or json:"-" fields)
+func labelKey(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
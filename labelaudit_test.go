@@ -0,0 +1,210 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+// auditTestLabels is a small labels struct for the audit tests
+type auditTestLabels struct {
+	Title   string `json:"title"`
+	Buttons struct {
+		Save   string `json:"save"`
+		Cancel string `json:"cancel"`
+	} `json:"buttons"`
+}
+
+func TestAuditLabelsFor(t *testing.T) {
+	en := `{"title": "Title", "buttons": {"save": "Save", "cancel": "Cancel"}}`
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name:  "complete",
+			files: map[string]string{"en.json": en},
+		},
+		{
+			name: "regional overlay only has differing keys",
+			files: map[string]string{
+				"en.json":    en,
+				"en-AU.json": `{"buttons": {"save": "Save it"}}`,
+			},
+		},
+		{
+			name: "regional overlay over a base language other than the default",
+			files: map[string]string{
+				"en.json":    en,
+				"fr.json":    `{"title": "Titre", "buttons": {"save": "Enregistrer"}}`,
+				"fr-CA.json": `{"buttons": {"cancel": "Annuler"}}`,
+			},
+			want: []string{"fr: buttons.cancel (missing)"},
+		},
+		{
+			name: "no fallback to the default locale",
+			files: map[string]string{
+				"en.json": en,
+				"fr.json": `{"title": "Titre"}`,
+			},
+			want: []string{"fr: buttons.cancel (missing)", "fr: buttons.save (missing)"},
+		},
+		{
+			name: "regional locale without a base file",
+			files: map[string]string{
+				"en.json":    en,
+				"pt-BR.json": `{"title": " ", "buttons": {"save": "Salvar", "cancel": "Cancelar"}}`,
+			},
+			want: []string{"pt-BR: title (empty)"},
+		},
+		{
+			name: "empty overlay value falls back to the base",
+			files: map[string]string{
+				"en.json":    en,
+				"en-AU.json": `{"title": ""}`,
+			},
+		},
+		{
+			name: "keys match case-insensitively like json.Unmarshal",
+			files: map[string]string{
+				"en.json": `{"Title": "Title", "BUTTONS": {"Save": "Save", "cancel": "Cancel"}}`,
+			},
+		},
+		{
+			name: "extra and invalid keys in an overlay",
+			files: map[string]string{
+				"en.json":    en,
+				"en-AU.json": `{"buttons": "Buttons", "subtitle": "Subtitle"}`,
+			},
+			want: []string{"en-AU: buttons (invalid)", "en-AU: subtitle (extra)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, content := range tt.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(content)}
+			}
+
+			issues, err := AuditLabelsFor(fsys, auditTestLabels{})
+			if err != nil {
+				t.Fatalf("AuditLabelsFor: %v", err)
+			}
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("issues = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditLabelFile(t *testing.T) {
+	issues, err := AuditLabelFile("en-AU", []byte(`{"buttons": {"save": "Save it"}}`), &auditTestLabels{})
+	if err != nil {
+		t.Fatalf("AuditLabelFile: %v", err)
+	}
+	// A single file is audited as written
+	if len(issues) != 2 {
+		t.Errorf("issues = %v, want title and buttons.cancel missing", issues)
+	}
+
+	if _, err := AuditLabelFile("en", []byte(`{}`), "labels"); err == nil {
+		t.Error("AuditLabelFile with a non-struct: want error")
+	}
+	if _, err := AuditLabelFile("en", []byte(`{`), auditTestLabels{}); err == nil {
+		t.Error("AuditLabelFile with invalid JSON: want error")
+	}
+}
+
+// recordingT records CheckLabels failures
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCheckLabels(t *testing.T) {
+	en, err := json.Marshal(filledLabels())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("default locale with a regional overlay", func(t *testing.T) {
+		rec := &recordingT{}
+		CheckLabels(rec, fstest.MapFS{
+			"en.json":    {Data: en},
+			"en-AU.json": {Data: []byte(`{"buttons": {"cancel": "Cancel"}}`)},
+		})
+		if len(rec.errors) != 0 {
+			t.Errorf("CheckLabels failed: %q", rec.errors)
+		}
+	})
+
+	t.Run("incomplete translation", func(t *testing.T) {
+		rec := &recordingT{}
+		CheckLabels(rec, fstest.MapFS{
+			"en.json": {Data: en},
+			"fr.json": {Data: []byte(`{"buttons": {"cancel": "Annuler"}, "unknown": "?"}`)},
+		})
+		if !slices.Contains(rec.errors, "label fr: unknown (extra)") {
+			t.Errorf("CheckLabels errors %q, want the extra key", rec.errors)
+		}
+		if slices.Contains(rec.errors, "label fr: buttons.cancel (missing)") {
+			t.Errorf("CheckLabels reported a translated key as missing")
+		}
+	})
+
+	t.Run("removed sidebar menu keys", func(t *testing.T) {
+		rec := &recordingT{}
+		CheckLabels(rec, fstest.MapFS{
+			"en.json":    {Data: en},
+			"en-AU.json": {Data: []byte(`{"sidebar": {"clients": {"title": "Clients"}}}`)},
+		})
+		if !slices.Contains(rec.errors, "label en-AU: sidebar.clients (extra)") {
+			t.Errorf("CheckLabels errors %q, want the removed key reported as extra", rec.errors)
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		rec := &recordingT{}
+		CheckLabels(rec, fstest.MapFS{"en.json": {Data: []byte(`{`)}})
+		if len(rec.errors) != 1 {
+			t.Errorf("CheckLabels errors = %q, want one audit failure", rec.errors)
+		}
+	})
+}
+
+// filledLabels returns CommonLabels with every string field set
+func filledLabels() CommonLabels {
+	var labels CommonLabels
+	fillStrings(reflect.ValueOf(&labels).Elem())
+	return labels
+}
+
+// fillStrings sets every exported string field of a struct value, recursively
+func fillStrings(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+		switch field := v.Field(i); field.Kind() {
+		case reflect.String:
+			field.SetString(v.Type().Field(i).Name)
+		case reflect.Struct:
+			fillStrings(field)
+		}
+	}
+}
@@ -22,106 +22,22 @@ type CommonLabels struct {
 	Card          CardLabels          `json:"card"`
 }
 
-// SidebarLabels holds sidebar navigation labels
+// SidebarLabels holds the sidebar's own labels. Navigation items are app-specific
+// and come from a Nav tree built from the app's own labels (see the Nav example).
 type SidebarLabels struct {
-	AppSwitcher AppSwitcherLabels        `json:"appSwitcher"`
-	Apps        AppLabels                `json:"apps"`
-	Clients     SidebarClientsLabels     `json:"clients"`
-	Regulations SidebarRegulationsLabels `json:"regulations"`
-	Marketplace SidebarMarketplaceLabels `json:"marketplace"`
-	Quotes      SidebarQuotesLabels      `json:"quotes"`
-	Users       SidebarUsersLabels       `json:"users"`
-	Reports     SidebarReportsLabels     `json:"reports"`
-	Main        SidebarMainLabels        `json:"main"`
-	Support     SidebarSupportLabels     `json:"support"`
-	UserMenu    UserMenuLabels           `json:"userMenu"`
-	UserCard    UserCardLabels           `json:"userCard"`
+	Collapse    string            `json:"collapse"`
+	Expand      string            `json:"expand"`
+	OpenMenu    string            `json:"openMenu"`
+	CloseMenu   string            `json:"closeMenu"`
+	AppSwitcher AppSwitcherLabels `json:"appSwitcher"`
+	UserMenu    UserMenuLabels    `json:"userMenu"`
+	UserCard    UserCardLabels    `json:"userCard"`
 }
 
 type AppSwitcherLabels struct {
 	SwitchApp string `json:"switchApp"`
 }
 
-type AppLabels struct {
-	Clients     string `json:"clients"`
-	Regulations string `json:"regulations"`
-	Marketplace string `json:"marketplace"`
-	Quotes      string `json:"quotes"`
-	Users       string `json:"users"`
-	Reports     string `json:"reports"`
-}
-
-type SidebarClientsLabels struct {
-	Title        string `json:"title"`
-	Active       string `json:"active"`
-	Prospect     string `json:"prospect"`
-	Inactive     string `json:"inactive"`
-	Settings     string `json:"settings"`
-	PaymentTerms string `json:"paymentTerms"`
-}
-
-type SidebarRegulationsLabels struct {
-	Title            string `json:"title"`
-	Awards           string `json:"awards"`
-	Agreements       string `json:"agreements"`
-	WorkersComp      string `json:"workersComp"`
-	Superannuation   string `json:"superannuation"`
-	PayrollTax       string `json:"payrollTax"`
-	Settings         string `json:"settings"`
-	PayItems         string `json:"payItems"`
-	PayItemsMapping  string `json:"payItemsMapping"`
-	AwardMultipliers string `json:"awardMultipliers"`
-	AdditionalItems  string `json:"additionalItems"`
-}
-
-type SidebarMarketplaceLabels struct {
-	Title      string `json:"title"`
-	CRM        string `json:"crm"`
-	RMS        string `json:"rms"`
-	Payroll    string `json:"payroll"`
-	Accounting string `json:"accounting"`
-}
-
-type SidebarQuotesLabels struct {
-	Title             string `json:"title"`
-	Calculation       string `json:"calculation"`
-	ApprovedSigned    string `json:"approvedSigned"`
-	ForApproval       string `json:"forApproval"`
-	Templates         string `json:"templates"`
-	Archived          string `json:"archived"`
-	Settings          string `json:"settings"`
-	QuoteTemplates    string `json:"quoteTemplates"`
-	ContractTemplates string `json:"contractTemplates"`
-	ProposalTemplates string `json:"proposalTemplates"`
-	CostManagement    string `json:"costManagement"`
-}
-
-type SidebarUsersLabels struct {
-	Title         string `json:"title"`
-	AdminManagers string `json:"adminManagers"`
-	Managers      string `json:"managers"`
-	Standard      string `json:"standard"`
-	Spectator     string `json:"spectator"`
-	Settings      string `json:"settings"`
-	UserDivisions string `json:"userDivisions"`
-}
-
-type SidebarReportsLabels struct {
-	Title       string `json:"title"`
-	Quote       string `json:"quote"`
-	AwardChanges string `json:"awardChanges"`
-}
-
-type SidebarMainLabels struct {
-	Title     string `json:"title"`
-	Dashboard string `json:"dashboard"`
-}
-
-type SidebarSupportLabels struct {
-	Title      string `json:"title"`
-	HelpCenter string `json:"helpCenter"`
-}
-
 type UserMenuLabels struct {
 	Profile string `json:"profile"`
 	Billing string `json:"billing"`
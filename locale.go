@@ -0,0 +1,341 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the locale labels fall back to when a key or locale file is missing
+const DefaultLocale = "en"
+
+// LocaleCookie is the cookie the user's chosen locale is read from
+const LocaleCookie = "locale"
+
+// LabelLoader loads CommonLabels from locale JSON files and caches them per locale.
+// Files are named by locale at the root of the filesystem (en.json, en-AU.json, fr.json);
+// use fs.Sub for a subdirectory. A locale is deep-merged over its base language and the
+// default locale, so missing or empty keys fall back: en-AU.json only needs the keys
+// that differ from en.json.
+type LabelLoader struct {
+	fsys          fs.FS
+	defaultLocale string
+	cookieName    string
+
+	mu      sync.RWMutex
+	locales []string                // available locales (file names without .json), listed on first use
+	cache   map[string]CommonLabels // merged labels by available locale
+}
+
+// NewLabelLoader creates a loader for the locale files in fsys
+func NewLabelLoader(fsys fs.FS) *LabelLoader {
+	return &LabelLoader{
+		fsys:          fsys,
+		defaultLocale: DefaultLocale,
+		cookieName:    LocaleCookie,
+		cache:         make(map[string]CommonLabels),
+	}
+}
+
+// WithDefaultLocale sets the fallback locale (default DefaultLocale). Its file must exist.
+func (l *LabelLoader) WithDefaultLocale(locale string) *LabelLoader {
+	l.defaultLocale = locale
+	return l
+}
+
+// WithCookie sets the cookie the user's locale is read from (default LocaleCookie, "" to disable)
+func (l *LabelLoader) WithCookie(name string) *LabelLoader {
+	l.cookieName = name
+	return l
+}
+
+// loaders caches a LabelLoader per filesystem for LoadLabels
+var loaders sync.Map
+
+// LoadLabels returns the labels for locale from the locale files in fsys, merged over
+// DefaultLocale (see LabelLoader). Results are cached per filesystem and locale.
+//
+// Example:
+//
+//	//go:embed locales
+//	var localeFS embed.FS
+//
+//	sub, _ := fs.Sub(localeFS, "locales")
+//	labels, err := ui.LoadLabels(sub, "en-AU")
+func LoadLabels(fsys fs.FS, locale string) (CommonLabels, error) {
+	// Filesystems that can't be map keys (e.g., fstest.MapFS) get an uncached loader
+	if fsys == nil || !reflect.TypeOf(fsys).Comparable() {
+		return NewLabelLoader(fsys).Load(locale)
+	}
+	loader, _ := loaders.LoadOrStore(fsys, NewLabelLoader(fsys))
+	return loader.(*LabelLoader).Load(locale)
+}
+
+// Load returns the labels for locale. Unknown locales resolve to their base language
+// (en-NZ to en) or the default locale.
+func (l *LabelLoader) Load(locale string) (CommonLabels, error) {
+	locales, err := l.Locales()
+	if err != nil {
+		return CommonLabels{}, err
+	}
+	locale = l.match(locales, locale)
+
+	l.mu.RLock()
+	labels, ok := l.cache[locale]
+	l.mu.RUnlock()
+	if ok {
+		return labels, nil
+	}
+
+	labels, err = l.load(locales, locale)
+	if err != nil {
+		return CommonLabels{}, err
+	}
+
+	l.mu.Lock()
+	l.cache[locale] = labels
+	l.mu.Unlock()
+	return labels, nil
+}
+
+// Locales returns the available locales, sorted
+func (l *LabelLoader) Locales() ([]string, error) {
+	l.mu.RLock()
+	locales := l.locales
+	l.mu.RUnlock()
+	if locales != nil {
+		return locales, nil
+	}
+
+	if l.fsys == nil {
+		return nil, fmt.Errorf("label loader has no filesystem")
+	}
+	files, err := fs.Glob(l.fsys, "*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list locale files: %w", err)
+	}
+	locales = make([]string, 0, len(files))
+	for _, file := range files {
+		locales = append(locales, strings.TrimSuffix(path.Base(file), ".json"))
+	}
+	sort.Strings(locales)
+
+	if findLocale(locales, l.defaultLocale) == "" {
+		return nil, fmt.Errorf("default locale file not found: %s.json", l.defaultLocale)
+	}
+
+	l.mu.Lock()
+	l.locales = locales
+	l.mu.Unlock()
+	return locales, nil
+}
+
+// Reset clears the cache so locale files are read again (e.g., after editing them in development)
+func (l *LabelLoader) Reset() {
+	l.mu.Lock()
+	l.locales = nil
+	l.cache = make(map[string]CommonLabels)
+	l.mu.Unlock()
+}
+
+// Resolve returns the available locale for a request: the locale cookie if set to an
+// available locale, otherwise the best Accept-Language match, otherwise the default.
+func (l *LabelLoader) Resolve(r *http.Request) string {
+	locales, err := l.Locales()
+	if err != nil {
+		return l.defaultLocale
+	}
+
+	if l.cookieName != "" {
+		if cookie, err := r.Cookie(l.cookieName); err == nil {
+			if locale := matchLocale(locales, cookie.Value); locale != "" {
+				return locale
+			}
+		}
+	}
+
+	for _, tag := range parseAcceptLanguage(r.Header.Get("Accept-Language")) {
+		if locale := matchLocale(locales, tag); locale != "" {
+			return locale
+		}
+	}
+	return l.match(locales, l.defaultLocale)
+}
+
+// ForRequest returns the labels for the request's locale: RequestData.Locale when set
+// (see Middleware), otherwise Resolve(r)
+func (l *LabelLoader) ForRequest(r *http.Request) (CommonLabels, error) {
+	locale := GetRequestData(r.Context()).Locale
+	if locale == "" {
+		locale = l.Resolve(r)
+	}
+	return l.Load(locale)
+}
+
+// Middleware resolves each request's locale (see Resolve) into RequestData.Locale,
+// where the "locale" template func and ForRequest read it
+func (l *LabelLoader) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := GetRequestData(r.Context())
+		data.Locale = l.Resolve(r)
+		next.ServeHTTP(w, r.WithContext(WithRequestData(r.Context(), data)))
+	})
+}
+
+// match returns the available locale to load for locale, falling back to the default
+func (l *LabelLoader) match(locales []string, locale string) string {
+	if match := matchLocale(locales, locale); match != "" {
+		return match
+	}
+	return findLocale(locales, l.defaultLocale)
+}
+
+// load reads and merges the default locale, the base language and the locale itself
+func (l *LabelLoader) load(locales []string, locale string) (CommonLabels, error) {
+	chain := []string{findLocale(locales, l.defaultLocale)}
+	if base := findLocale(locales, baseLanguage(locale)); base != "" && base != chain[0] {
+		chain = append(chain, base)
+	}
+	if locale != chain[len(chain)-1] {
+		chain = append(chain, locale)
+	}
+
+	merged := map[string]any{}
+	for _, name := range chain {
+		content, err := fs.ReadFile(l.fsys, name+".json")
+		if err != nil {
+			return CommonLabels{}, fmt.Errorf("failed to read locale %s: %w", name, err)
+		}
+		var values map[string]any
+		if err := json.Unmarshal(content, &values); err != nil {
+			return CommonLabels{}, fmt.Errorf("failed to parse locale %s: %w", name, err)
+		}
+		mergeLabelValues(merged, values)
+	}
+
+	// Round-trip through JSON to fill the struct from the merged tree
+	content, err := json.Marshal(merged)
+	if err != nil {
+		return CommonLabels{}, fmt.Errorf("failed to merge locale %s: %w", locale, err)
+	}
+	var labels CommonLabels
+	if err := json.Unmarshal(content, &labels); err != nil {
+		return CommonLabels{}, fmt.Errorf("failed to decode locale %s: %w", locale, err)
+	}
+	return labels, nil
+}
+
+// mergeLabelValues deep-merges src into dst. Empty strings don't replace a value.
+// Keys match case-insensitively, as json.Unmarshal matches them to struct fields, and
+// the merged value takes src's spelling, so "title" in a regional file overrides "Title".
+func mergeLabelValues(dst, src map[string]any) {
+	for key, value := range src {
+		for existing := range dst {
+			if existing != key && strings.EqualFold(existing, key) {
+				if _, ok := dst[key]; !ok {
+					dst[key] = dst[existing]
+				}
+				delete(dst, existing)
+			}
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			existing, ok := dst[key].(map[string]any)
+			if !ok {
+				existing = map[string]any{}
+				dst[key] = existing
+			}
+			mergeLabelValues(existing, v)
+		case string:
+			if v != "" || dst[key] == nil {
+				dst[key] = v
+			}
+		default:
+			dst[key] = v
+		}
+	}
+}
+
+// matchLocale returns the available locale matching tag exactly (ignoring case and
+// "_" vs "-"), else its base language, else "" (e.g., "en_au" -> "en-AU", "fr-CA" -> "fr")
+func matchLocale(locales []string, tag string) string {
+	if locale := findLocale(locales, tag); locale != "" {
+		return locale
+	}
+	return findLocale(locales, baseLanguage(tag))
+}
+
+// findLocale returns the available locale equal to tag (ignoring case and "_" vs "-"), or ""
+func findLocale(locales []string, tag string) string {
+	tag = normalizeLocale(tag)
+	if tag == "" {
+		return ""
+	}
+	for _, locale := range locales {
+		if normalizeLocale(locale) == tag {
+			return locale
+		}
+	}
+	return ""
+}
+
+// normalizeLocale lowercases a locale tag and uses "-" as separator
+func normalizeLocale(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
+
+// baseLanguage returns the language of a locale tag (e.g., "en-AU" -> "en")
+func baseLanguage(tag string) string {
+	tag = normalizeLocale(tag)
+	if i := strings.IndexByte(tag, '-'); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
+
+// parseAcceptLanguage returns the language tags of an Accept-Language header by
+// descending quality (e.g., "fr-CA,fr;q=0.9,en;q=0.8" -> fr-CA, fr, en)
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag: tag, q: q})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMergeLabelValues(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  map[string]any
+		src  map[string]any
+		want map[string]any
+	}{
+		{
+			name: "override replaces the base value",
+			dst:  map[string]any{"title": "Base"},
+			src:  map[string]any{"title": "Override"},
+			want: map[string]any{"title": "Override"},
+		},
+		{
+			name: "empty string keeps the base value",
+			dst:  map[string]any{"title": "Base"},
+			src:  map[string]any{"title": ""},
+			want: map[string]any{"title": "Base"},
+		},
+		{
+			name: "keys differing in case merge under the override's spelling",
+			dst:  map[string]any{"Title": "Base"},
+			src:  map[string]any{"title": "Override"},
+			want: map[string]any{"title": "Override"},
+		},
+		{
+			name: "empty override with another spelling keeps the base value",
+			dst:  map[string]any{"Title": "Base"},
+			src:  map[string]any{"title": ""},
+			want: map[string]any{"title": "Base"},
+		},
+		{
+			name: "nested objects merge case-insensitively",
+			dst:  map[string]any{"Errors": map[string]any{"NotFound": "Missing", "general": "Oops"}},
+			src:  map[string]any{"errors": map[string]any{"notFound": "Not here"}},
+			want: map[string]any{"errors": map[string]any{"notFound": "Not here", "general": "Oops"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mergeLabelValues(tt.dst, tt.src)
+			if !reflect.DeepEqual(tt.dst, tt.want) {
+				t.Errorf("merged = %v, want %v", tt.dst, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadLabelsRegionalOverrideIgnoresKeyCase(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.json":    {Data: []byte(`{"Errors": {"NotFound": "Not found", "General": "Error"}}`)},
+		"en-AU.json": {Data: []byte(`{"errors": {"notFound": "Not found, mate"}}`)},
+	}
+
+	for i := 0; i < 20; i++ { // map order varies between runs
+		labels, err := LoadLabels(fsys, "en-AU")
+		if err != nil {
+			t.Fatalf("LoadLabels: %v", err)
+		}
+		if labels.Errors.NotFound != "Not found, mate" || labels.Errors.General != "Error" {
+			t.Fatalf("Errors = %+v, want the en-AU NotFound over the en General", labels.Errors)
+		}
+	}
+}
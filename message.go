@@ -0,0 +1,545 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Messages use a subset of the ICU MessageFormat syntax, shared with the JS formatter
+// (assets/js/message.js):
+//
+//	{name}                                      argument value
+//	{{name}}                                    same (legacy placeholder)
+//	{count, plural, =0 {none} one {# item} other {# items}}
+//	{count, plural, offset:1 one {you and # other} other {you and # others}}
+//	{role, select, admin {Administrator} other {Member}}
+//	'{literal}'                                 quoted text ('' is an apostrophe)
+//
+// Inside a plural case, # is the number (minus the offset). Plural categories
+// (zero, one, two, few, many, other) follow the CLDR rules of the locale (see PluralCategory).
+
+// msgNode is an element of a parsed message
+type msgNode any
+
+// msgArg is a {name} placeholder
+type msgArg struct {
+	name string
+}
+
+// msgPound is # inside a plural case
+type msgPound struct{}
+
+// msgChoice is a {name, plural, ...} or {name, select, ...} argument
+type msgChoice struct {
+	name   string
+	plural bool
+	offset float64
+	cases  map[string][]msgNode // keyed by "=N", plural category or select value
+}
+
+// parsedMessages caches parsed patterns (label strings are few and reused)
+var parsedMessages sync.Map
+
+// FormatMessage formats an ICU-style message (see above) for locale with named args.
+// Unknown arguments render as "{name}" and are reported in the error, so the result
+// is usable even when the error is non-nil.
+//
+// Example:
+//
+//	s, err := ui.FormatMessage("en", "{count, plural, one {# item} other {# items}} selected",
+//	    map[string]any{"count": 3}) // "3 items selected"
+func FormatMessage(locale, pattern string, args map[string]any) (string, error) {
+	nodes, err := parseMessageCached(pattern)
+	if err != nil {
+		return pattern, err
+	}
+
+	f := &msgFormatter{locale: locale, args: args}
+	var b strings.Builder
+	f.format(&b, nodes, 0, false)
+	if len(f.missing) > 0 {
+		return b.String(), fmt.Errorf("missing message arguments: %s", strings.Join(f.missing, ", "))
+	}
+	return b.String(), nil
+}
+
+// messageFunc returns the "msg" template function for a locale.
+// Usage: {{msg "{count, plural, one {# client} other {# clients}}" "count" .Total}}
+// or with a map of args: {{msg .Labels.Bulk.Selected .Args}}
+func messageFunc(locale string) func(pattern string, args ...any) string {
+	return func(pattern string, args ...any) string {
+		values := make(map[string]any, len(args)/2)
+		if len(args) == 1 {
+			if m, ok := args[0].(map[string]any); ok {
+				values = m
+			}
+		} else {
+			for i := 0; i+1 < len(args); i += 2 {
+				if key, ok := args[i].(string); ok {
+					values[key] = args[i+1]
+				}
+			}
+		}
+
+		s, err := FormatMessage(locale, pattern, values)
+		if err != nil {
+			log.Printf("Warning: Failed to format message %q: %v", pattern, err)
+		}
+		return s
+	}
+}
+
+// PluralCategory returns the CLDR cardinal plural category of n for locale:
+// "zero", "one", "two", "few", "many" or "other". The rules follow current CLDR (as
+// Intl.PluralRules in current browsers) for these languages only:
+//
+//	other only   ja zh ko vi th id ms lo my km
+//	one          en de nl sv da nb no fi et el hu tr bg
+//	one, many    fr es it pt (pt-PT separately)
+//	one..many    ru uk be pl cs sk
+//	zero..many   ar
+//	one, two     he
+//
+// Other languages use the English rule (one for exactly 1, otherwise other);
+// assets/js/message.js applies the same fallback so both sides agree.
+func PluralCategory(locale string, n float64) string {
+	abs := math.Abs(n)
+	i := int64(abs)
+	integer := abs == math.Trunc(abs) // v = 0 (no visible fraction digits)
+
+	// fr, es, it and pt: "many" for whole millions (1000000, 2000000, ...)
+	million := integer && i != 0 && i%1000000 == 0
+
+	switch pluralLanguage(locale) {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms", "lo", "my", "km":
+		return "other"
+	case "fr", "pt":
+		switch {
+		case i == 0 || i == 1:
+			return "one"
+		case million:
+			return "many"
+		}
+	case "es":
+		switch {
+		case abs == 1:
+			return "one"
+		case million:
+			return "many"
+		}
+	case "it", "pt-pt":
+		switch {
+		case integer && i == 1:
+			return "one"
+		case million:
+			return "many"
+		}
+	case "ru", "uk", "be":
+		if !integer {
+			return "other"
+		}
+		switch {
+		case i%10 == 1 && i%100 != 11:
+			return "one"
+		case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	case "pl":
+		if !integer {
+			return "other"
+		}
+		switch {
+		case i == 1:
+			return "one"
+		case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	case "cs", "sk":
+		switch {
+		case !integer:
+			return "many"
+		case i == 1:
+			return "one"
+		case i >= 2 && i <= 4:
+			return "few"
+		}
+	case "ar":
+		if !integer {
+			return "other"
+		}
+		switch {
+		case i == 0:
+			return "zero"
+		case i == 1:
+			return "one"
+		case i == 2:
+			return "two"
+		case i%100 >= 3 && i%100 <= 10:
+			return "few"
+		case i%100 >= 11:
+			return "many"
+		}
+	case "he":
+		switch {
+		case integer && i == 1, !integer && i == 0:
+			return "one"
+		case integer && i == 2:
+			return "two"
+		}
+	case "da":
+		if abs == 1 || (!integer && (i == 0 || i == 1)) {
+			return "one"
+		}
+	default:
+		if integer && i == 1 {
+			return "one"
+		}
+	}
+	return "other"
+}
+
+// pluralLanguage returns the plural rule set of a locale: its base language, or
+// "pt-pt" for European Portuguese
+func pluralLanguage(locale string) string {
+	if normalizeLocale(locale) == "pt-pt" {
+		return "pt-pt"
+	}
+	return baseLanguage(locale)
+}
+
+// msgFormatter renders parsed nodes
+type msgFormatter struct {
+	locale  string
+	args    map[string]any
+	missing []string
+}
+
+// format writes nodes; number is the value # stands for inside a plural case
+func (f *msgFormatter) format(b *strings.Builder, nodes []msgNode, number float64, inPlural bool) {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case string:
+			b.WriteString(n)
+		case msgPound:
+			if inPlural {
+				b.WriteString(formatMessageNumber(number))
+			} else {
+				b.WriteString("#")
+			}
+		case msgArg:
+			value, ok := f.args[n.name]
+			if !ok {
+				f.missing = append(f.missing, n.name)
+				b.WriteString("{" + n.name + "}")
+				continue
+			}
+			b.WriteString(formatMessageValue(value))
+		case msgChoice:
+			value, ok := f.args[n.name]
+			if !ok {
+				f.missing = append(f.missing, n.name)
+			}
+			if !n.plural {
+				selected, ok := n.cases[fmt.Sprint(value)]
+				if !ok {
+					selected = n.cases["other"]
+				}
+				f.format(b, selected, number, inPlural)
+				continue
+			}
+
+			count, _ := messageNumber(value)
+			selected, ok := n.cases["="+formatMessageNumber(count)]
+			if !ok {
+				selected, ok = n.cases[PluralCategory(f.locale, count-n.offset)]
+			}
+			if !ok {
+				selected = n.cases["other"]
+			}
+			f.format(b, selected, count-n.offset, true)
+		}
+	}
+}
+
+// formatMessageValue formats an argument value
+func formatMessageValue(value any) string {
+	if n, ok := messageNumber(value); ok {
+		if _, isString := value.(string); !isString {
+			return formatMessageNumber(n)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// formatMessageNumber formats a number without exponent or trailing zeros
+func formatMessageNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// messageNumber converts a numeric argument (any int, uint or float type, or a numeric string)
+func messageNumber(value any) (float64, bool) {
+	if s, ok := value.(string); ok {
+		n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		return n, err == nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// parseMessageCached parses a pattern once
+func parseMessageCached(pattern string) ([]msgNode, error) {
+	if nodes, ok := parsedMessages.Load(pattern); ok {
+		return nodes.([]msgNode), nil
+	}
+	p := &msgParser{src: pattern}
+	nodes, err := p.parse(false, 0)
+	if err != nil {
+		return nil, err
+	}
+	parsedMessages.Store(pattern, nodes)
+	return nodes, nil
+}
+
+// msgParser is a recursive-descent parser for message patterns
+type msgParser struct {
+	src string
+	pos int
+}
+
+// parse reads text and arguments until the end of input, or the closing brace of
+// the enclosing case when depth > 0
+func (p *msgParser) parse(inPlural bool, depth int) ([]msgNode, error) {
+	var nodes []msgNode
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, text.String())
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\'':
+			p.readQuoted(&text, inPlural)
+		case c == '#' && inPlural:
+			flush()
+			nodes = append(nodes, msgPound{})
+			p.pos++
+		case c == '}':
+			if depth == 0 {
+				return nil, p.errorf("unexpected }")
+			}
+			flush()
+			return nodes, nil
+		case c == '{':
+			flush()
+			node, err := p.parseArgument(depth)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	if depth > 0 {
+		return nil, p.errorf("unclosed {")
+	}
+	flush()
+	return nodes, nil
+}
+
+// readQuoted handles an apostrophe: two in a row are a literal apostrophe, and an apostrophe
+// before a syntax character quotes text up to the next single apostrophe
+func (p *msgParser) readQuoted(text *strings.Builder, inPlural bool) {
+	p.pos++
+	if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+		text.WriteByte('\'')
+		p.pos++
+		return
+	}
+	if p.pos >= len(p.src) || !(p.src[p.pos] == '{' || p.src[p.pos] == '}' || (inPlural && p.src[p.pos] == '#')) {
+		text.WriteByte('\'')
+		return
+	}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		if c != '\'' {
+			text.WriteByte(c)
+			continue
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+			text.WriteByte('\'')
+			p.pos++
+			continue
+		}
+		return
+	}
+}
+
+// parseArgument reads {name}, {{name}}, {name, number}, {name, plural, ...} or {name, select, ...}
+func (p *msgParser) parseArgument(depth int) (msgNode, error) {
+	// Legacy {{name}} placeholder
+	if strings.HasPrefix(p.src[p.pos:], "{{") {
+		end := strings.Index(p.src[p.pos:], "}}")
+		if end > 0 {
+			name := strings.TrimSpace(p.src[p.pos+2 : p.pos+end])
+			if isMessageIdent(name) {
+				p.pos += end + 2
+				return msgArg{name: name}, nil
+			}
+		}
+	}
+
+	p.pos++ // {
+	p.skipSpace()
+	name := p.readIdent()
+	if name == "" {
+		return nil, p.errorf("expected argument name")
+	}
+
+	p.skipSpace()
+	if p.consume('}') {
+		return msgArg{name: name}, nil
+	}
+	if !p.consume(',') {
+		return nil, p.errorf("expected , or } after %s", name)
+	}
+
+	p.skipSpace()
+	kind := p.readIdent()
+	p.skipSpace()
+	switch kind {
+	case "number":
+		// Style arguments (e.g. {n, number, integer}) are accepted and ignored
+		if end := strings.IndexByte(p.src[p.pos:], '}'); end >= 0 {
+			p.pos += end + 1
+			return msgArg{name: name}, nil
+		}
+		return nil, p.errorf("unclosed {")
+	case "plural", "select":
+	default:
+		return nil, p.errorf("unknown argument type %q", kind)
+	}
+	if !p.consume(',') {
+		return nil, p.errorf("expected , after %s", kind)
+	}
+
+	choice := msgChoice{name: name, plural: kind == "plural", cases: map[string][]msgNode{}}
+	for {
+		p.skipSpace()
+		if p.consume('}') {
+			break
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unclosed {")
+		}
+
+		key := p.readSelector()
+		if key == "" {
+			return nil, p.errorf("expected case selector")
+		}
+		if choice.plural && strings.HasPrefix(key, "offset:") {
+			offset, err := strconv.ParseFloat(strings.TrimPrefix(key, "offset:"), 64)
+			if err != nil {
+				return nil, p.errorf("invalid offset %q", key)
+			}
+			choice.offset = offset
+			continue
+		}
+
+		p.skipSpace()
+		if !p.consume('{') {
+			return nil, p.errorf("expected { after case %s", key)
+		}
+		nodes, err := p.parse(choice.plural, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		p.pos++ // }
+		choice.cases[key] = nodes
+	}
+
+	if _, ok := choice.cases["other"]; !ok {
+		return nil, p.errorf("%s argument %s has no other case", kind, name)
+	}
+	return choice, nil
+}
+
+func (p *msgParser) readIdent() string {
+	start := p.pos
+	for p.pos < len(p.src) && isMessageIdentByte(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// readSelector reads a case key: "=0", "one", "offset:1" or a select value
+func (p *msgParser) readSelector() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == '{' || c == '}' || c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *msgParser) skipSpace() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\n\r", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *msgParser) consume(c byte) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *msgParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid message at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func isMessageIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isMessageIdentByte(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isMessageIdentByte(c byte) bool {
+	return c == '_' || c == '-' || c == '.' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultLayout is the layout template pages are rendered through (see partials/layout.html).
+// Apps can replace it by defining their own "layout" template in templatePatterns.
+const DefaultLayout = "layout"
+
+// WithPages enables per-page template sets. Every .html file below dir becomes a page
+// named by its path relative to dir without the extension (e.g., dir/clients/list.html
+// is "clients/list").
+//
+// Each page is parsed into its own clone of the shared set (components, partials and
+// the app's templatePatterns), so pages can override the layout's named blocks
+// ("content", "page-title", "page-head", ...) without colliding with other pages.
+// Rendering a page executes the layout (DefaultLayout unless set via WithLayout).
+//
+// Example page (templates/pages/clients/list.html):
+//
+//	{{define "page-title"}}Clients{{end}}
+//	{{define "content"}}
+//	    {{template "table-card" .Table}}
+//	{{end}}
+//
+// Rendered with: renderer.Render(w, "clients/list", data)
+func (r *HTMLRenderer) WithPages(dir string) *HTMLRenderer {
+	r.pagesDir = dir
+	return r
+}
+
+// WithLayout sets the layout template pages are rendered through (default DefaultLayout)
+func (r *HTMLRenderer) WithLayout(name string) *HTMLRenderer {
+	r.layoutName = name
+	return r
+}
+
+// GetPage returns the layout template of a page's isolated set, or nil if there is no such page
+func (r *HTMLRenderer) GetPage(name string) *template.Template {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.pages[name]
+}
+
+// parsePages clones base once per page file and parses the page into the clone.
+// Returns the layout template of every page's set, keyed by page name.
+func (r *HTMLRenderer) parsePages(base *template.Template) (map[string]*template.Template, error) {
+	if r.pagesDir == "" {
+		return nil, nil
+	}
+
+	files, err := r.pageFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	layout := r.layoutName
+	if layout == "" {
+		layout = DefaultLayout
+	}
+	if base.Lookup(layout) == nil {
+		return nil, fmt.Errorf("layout template not found: %s", layout)
+	}
+
+	pages := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		name := r.pageName(file)
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read page %s: %w", file, err)
+		}
+
+		set, err := base.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("failed to clone templates for page %s: %w", name, err)
+		}
+
+		if _, err := set.New(name).Parse(string(content)); err != nil {
+			log.Printf("Failed to parse page %s: %v", name, err)
+			return nil, err
+		}
+
+		pages[name] = set.Lookup(layout)
+	}
+
+	log.Printf("Parsed %d pages from: %s", len(pages), r.pagesDir)
+	return pages, nil
+}
+
+// pageFiles returns every .html file below the pages directory
+func (r *HTMLRenderer) pageFiles() ([]string, error) {
+	if r.pagesDir == "" {
+		return nil, nil
+	}
+
+	var files []string
+	err := filepath.WalkDir(r.pagesDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".html" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pages in %s: %w", r.pagesDir, err)
+	}
+	return files, nil
+}
+
+// pageName derives a page name from its file path (e.g., "pages/clients/list.html" -> "clients/list")
+func (r *HTMLRenderer) pageName(file string) string {
+	rel, err := filepath.Rel(r.pagesDir, file)
+	if err != nil {
+		rel = file
+	}
+	return strings.TrimSuffix(filepath.ToSlash(rel), ".html")
+}
@@ -0,0 +1,568 @@
+package ui
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"leapfor.xyz/pyeza-golang/types"
+)
+
+// Component props.
+//
+// Each struct below mirrors the dict keys its component reads, so templates accept
+// either the struct or the legacy dict:
+//
+//	{{template "toast" dict "Message" "Saved." "State" "success"}}
+//	{{template "toast" .Toast}} // .Toast is a ToastProps
+//
+// Every field a template reads must exist on its struct; a missing field is an
+// execution error for structs, while a missing dict key silently renders nothing.
+// Boolean props that default to true in the template (Toast.Dismissible, Alert.Icon,
+// Badge.ShowDot) are false in a zero struct, so build props with the New* constructors
+// to get the template defaults.
+
+// AlertProps holds the props of the "alert" component
+type AlertProps struct {
+	Message     string // Alert body text (required)
+	State       string // success | error | warning | info (default: info)
+	Title       string // Optional bold heading
+	Dismissible bool   // Shows close button
+	Variant     string // filled | outlined | subtle (default: subtle)
+	Icon        bool   // Shows state icon (true in NewAlertProps)
+	Class       string // Additional CSS classes
+	ID          string // Element ID
+}
+
+// NewAlertProps creates alert props with the state icon shown
+func NewAlertProps(state, message string) AlertProps {
+	return AlertProps{State: state, Message: message, Icon: true}
+}
+
+// AvatarProps holds the props of the "avatar" component
+type AvatarProps struct {
+	Name     string // Full name (used for alt text)
+	Fallback string // Text shown when there is no image (e.g., initials "JD")
+	Src      string // Image URL
+	Size     string // xs | sm | md | lg | xl (default: md)
+	SizeRem  string // Custom size in rem (e.g., "5"); overrides Size
+	Status   string // online | offline | busy | away
+	Color    string // terracotta | sage | navy | amber | plum
+	Class    string // Additional CSS classes
+	ID       string // Element ID
+}
+
+// NewAvatarProps creates avatar props for a name with its initials as fallback
+func NewAvatarProps(name, src string) AvatarProps {
+	return AvatarProps{Name: name, Src: src, Fallback: initials(name)}
+}
+
+// BadgeProps holds the props of the "badge" component and its shorthands
+// ("status-badge", "type-badge", "count-badge", "tag-badge")
+type BadgeProps struct {
+	Label   string // Badge text content
+	Variant string // status | count | type | category | tag | nav | tab | section (default: status)
+	Status  string // For status variant: active | inactive | prospect | draft | archived | pending | success | warning | error
+	Type    string // For type variant: award | eba | contractor
+	Tag     string // For tag variant: popular | connected | new
+	NavType string // For nav variant: modifier class
+	ShowDot bool   // Shows status dot for status variant (true in NewBadgeProps)
+	Size    string // sm | md (default: md)
+	Count   int    // For "count-badge"
+	Class   string // Additional CSS classes
+	ID      string // Element ID
+}
+
+// NewBadgeProps creates badge props with the status dot shown
+func NewBadgeProps(label, variant string) BadgeProps {
+	return BadgeProps{Label: label, Variant: variant, ShowDot: true}
+}
+
+// NewStatusBadgeProps creates props for a status badge (e.g., "Active", "active")
+func NewStatusBadgeProps(label, status string) BadgeProps {
+	return BadgeProps{Label: label, Variant: "status", Status: status, ShowDot: true}
+}
+
+// NewCountBadgeProps creates props for "count-badge"
+func NewCountBadgeProps(count int) BadgeProps {
+	return BadgeProps{Count: count, Variant: "count"}
+}
+
+// ButtonProps holds the props of the "button" component
+type ButtonProps struct {
+	Label      string            // Button text (optional for icon-only buttons)
+	Variant    string            // primary | secondary | ghost | white | outline | danger | success | icon | action (default: primary)
+	Size       string            // sm | md | lg | full (default: md)
+	Icon       string            // Icon name (e.g., "plus", "edit", "trash-2")
+	IconPos    string            // left | right (default: left)
+	Href       string            // Renders an <a> instead of a <button>
+	Type       string            // button | submit | reset (default: button)
+	Disabled   bool              // Disables the button
+	Title      string            // Tooltip/title attribute
+	ID         string            // Element ID
+	Class      string            // Additional CSS classes
+	ActionType string            // For action variant: view | edit | clone | delete
+	Data       map[string]string // data-* attributes (key without the "data-" prefix)
+}
+
+// NewButtonProps creates button props
+func NewButtonProps(label, variant string) ButtonProps {
+	return ButtonProps{Label: label, Variant: variant}
+}
+
+// NewLinkButtonProps creates props for a button rendered as a link
+func NewLinkButtonProps(label, variant, href string) ButtonProps {
+	return ButtonProps{Label: label, Variant: variant, Href: href}
+}
+
+// ButtonGroupProps holds the props of the "button-group" component
+type ButtonGroupProps struct {
+	Align string // left | center | right | between (default: left)
+	Gap   string // sm | md | lg (default: md)
+	Class string // Additional CSS classes
+}
+
+// CardProps holds the props of the "card" and "card-header" components
+type CardProps struct {
+	Title     string        // Card title (header is hidden when empty)
+	Icon      string        // Header icon name
+	IconColor string        // Header icon color class
+	Actions   template.HTML // Header actions
+	Body      template.HTML // Card body ("card" only)
+	Class     string        // Additional CSS classes
+}
+
+// QuickLinkCardProps holds the props of the "quick-link-card" component
+type QuickLinkCardProps struct {
+	Title       string // Card title
+	Description string // Card description
+	Icon        string // phone | play | message-square | mail | help | book | file | users
+	Color       string // Icon color class (e.g., "terracotta")
+	Href        string // Link URL
+}
+
+// ArticleCardProps holds the props of the "article-card" component
+type ArticleCardProps struct {
+	Title         string // Article title
+	Summary       string // Article summary
+	Badge         string // Badge text (optional)
+	BadgeType     string // Badge modifier class (e.g., "how-to")
+	ShowReadMore  bool   // Shows the read more link
+	ReadMoreLabel string // Read more text
+}
+
+// ChipProps holds the props of the "chip" component
+type ChipProps struct {
+	ID       string // Unique identifier (data-id attribute)
+	Code     string // Display text
+	Label    string // Longer label, used when Code is empty
+	Selected bool   // Selected state
+	Name     string // Form input name
+	Class    string // Additional CSS classes
+	Disabled bool   // Disables the chip
+}
+
+// NewChipProps creates chip props
+func NewChipProps(id, code string, selected bool) ChipProps {
+	return ChipProps{ID: id, Code: code, Selected: selected}
+}
+
+// ChipGroupProps holds the props of the "chip-group" component
+type ChipGroupProps struct {
+	Title   string   // Section title
+	Count   int      // Badge count next to the title
+	Chips   []string // Chip codes
+	ShowAdd bool     // Shows an add button at the end
+	Class   string   // Additional CSS classes
+}
+
+// DialogConfirmProps holds the props of the "dialog-confirm" fragment
+type DialogConfirmProps struct {
+	Title       string // Dialog title
+	Message     string // Confirmation message
+	ConfirmText string // Confirm button text
+	CancelText  string // Cancel button text
+	ActionURL   string // hx-post target of the confirm button (optional)
+	Variant     string // Confirm button variant (e.g., "danger")
+}
+
+// NewDialogConfirmProps creates confirm dialog props using the common button labels
+func NewDialogConfirmProps(title, message, actionURL string, labels ButtonLabels) DialogConfirmProps {
+	return DialogConfirmProps{
+		Title:       title,
+		Message:     message,
+		ActionURL:   actionURL,
+		ConfirmText: labels.Confirm,
+		CancelText:  labels.Cancel,
+		Variant:     "danger",
+	}
+}
+
+// DropdownOption is an option of the "dropdown-filter" component
+type DropdownOption struct {
+	Value  string // data-filter value
+	Label  string // Display text
+	Active bool   // Currently selected
+}
+
+// DropdownItem is an item of the "dropdown-action" and "dropdown-icon" components
+type DropdownItem struct {
+	Action  string // data-action value
+	Label   string // Display text
+	Icon    string // eye | edit | copy | trash | download | upload | archive | settings | plus
+	Danger  bool   // Danger styling
+	Divider bool   // Renders a separator instead of an item
+}
+
+// DropdownProps holds the props of the "dropdown-filter", "dropdown-action" and "dropdown-icon" components
+type DropdownProps struct {
+	ID       string           // Unique ID
+	Label    string           // Button label (current selection for filters)
+	Header   string           // Filter menu header text
+	Icon     string           // Filter button icon (filter)
+	Title    string           // Icon button title ("dropdown-icon")
+	Options  []DropdownOption // Filter options
+	Items    []DropdownItem   // Action items
+	Position string           // left | right alignment of action menus
+	Class    string           // Additional CSS classes
+}
+
+// EmptyStateProps holds the props of the "empty-state" component
+type EmptyStateProps struct {
+	Icon    string // Icon name (e.g., "icon-users")
+	Title   string // Main heading (required)
+	Desc    string // Helper text below the title
+	BtnText string // Action button text (button hidden when empty)
+	BtnHref string // Action button link
+	BtnIcon string // Action button icon
+	Class   string // Additional CSS classes
+}
+
+// NewEmptyStateProps creates empty state props
+func NewEmptyStateProps(icon, title, desc string) EmptyStateProps {
+	return EmptyStateProps{Icon: icon, Title: title, Desc: desc}
+}
+
+// FormGroupProps holds the props of the "form-group" component
+type FormGroupProps struct {
+	Type         string         // text | email | tel | select | textarea | date | number | password | url (default: text)
+	Name         string         // Field name
+	ID           string         // Element ID (defaults to Name)
+	Label        string         // Label text
+	Placeholder  string         // Placeholder text
+	Value        string         // Current value
+	Hint         string         // Help text below the input
+	Required     bool           // Marks the field as required
+	Disabled     bool           // Disables the input
+	Readonly     bool           // Makes the input readonly
+	Error        string         // Error message (triggers the error state)
+	Options      []SelectOption // Options for select
+	Rows         int            // Textarea rows (default: 4)
+	Min          string         // Min value for number/date
+	Max          string         // Max value for number/date
+	Step         string         // Step for number input
+	Pattern      string         // Validation pattern
+	Autocomplete string         // Autocomplete attribute
+	Class        string         // Additional CSS classes
+}
+
+// NewFormGroupProps creates form group props
+func NewFormGroupProps(fieldType, name, label string) FormGroupProps {
+	return FormGroupProps{Type: fieldType, Name: name, Label: label}
+}
+
+// NewSelectProps creates form group props for a select
+func NewSelectProps(name, label string, options []SelectOption) FormGroupProps {
+	return FormGroupProps{Type: "select", Name: name, Label: label, Options: options}
+}
+
+// FormRowProps holds the props of the "form-row" component
+type FormRowProps struct {
+	Layout  string        // single | double | thirds (default: double)
+	Content template.HTML // Form groups
+	Class   string        // Additional CSS classes
+}
+
+// FormSectionProps holds the props of the "form-section" component
+type FormSectionProps struct {
+	Title string // Section title
+	Class string // Additional CSS classes
+}
+
+// MultiSelectProps holds the props of the "multi-select" component
+type MultiSelectProps struct {
+	ID                   string         // Element ID (required)
+	Name                 string         // Form field name
+	Placeholder          string         // Shown when nothing is selected
+	SearchPlaceholder    string         // Search input placeholder
+	Options              []SelectOption // All options
+	Selected             []SelectOption // Selected options (rendered as chips)
+	Required             bool           // Marks the field as required
+	HelpText             string         // Help text below the field
+	NoResults            string         // Text when the search matches nothing (default: "No results found")
+	MaxVisibleChips      int            // Chips shown before "+N more" (default: 2)
+	MoreSelectedTemplate string         // Overflow message formatted with count (default: "+{count} more")
+}
+
+// NewMultiSelectProps creates multi-select props, deriving Selected from the selected options
+func NewMultiSelectProps(id, name string, options []SelectOption) MultiSelectProps {
+	props := MultiSelectProps{ID: id, Name: name, Options: options}
+	for _, opt := range options {
+		if opt.Selected {
+			props.Selected = append(props.Selected, opt)
+		}
+	}
+	return props
+}
+
+// NotificationCardProps holds the props of the "notification-card" component
+type NotificationCardProps struct {
+	ID      string // Element ID
+	Type    string // alert | success | info | warning | client | quote | system (default: info)
+	Title   string // Notification title
+	Message string // Notification message
+	Time    string // Relative time (e.g., "2 minutes ago")
+	Unread  bool   // Unread state (shows dismiss button)
+	Link    string // Link URL
+}
+
+// PaginationProps holds the props of the "pagination" components
+type PaginationProps struct {
+	CurrentPage  int              // Current page (1-based)
+	TotalPages   int              // Total number of pages
+	HasPrev      bool             // A previous page exists
+	HasNext      bool             // A next page exists
+	Pages        []int            // Page buttons; 0 is an ellipsis
+	Labels       PaginationLabels // Button and info labels
+	HasMore      bool             // For "pagination-load-more"
+	ShowingCount int              // For "pagination-load-more"
+	TotalCount   int              // For "pagination-load-more"
+}
+
+// NewPaginationProps creates pagination props with windowed page numbers
+func NewPaginationProps(currentPage, totalPages int, labels PaginationLabels) PaginationProps {
+	return PaginationProps{
+		CurrentPage: currentPage,
+		TotalPages:  totalPages,
+		HasPrev:     currentPage > 1,
+		HasNext:     currentPage < totalPages,
+		Pages:       types.PageWindow(currentPage, totalPages),
+		Labels:      labels,
+	}
+}
+
+// SheetProps holds the props of the "sheet" component
+type SheetProps struct {
+	ID            string        // Element ID (default: sheet)
+	Variant       string        // form | notification (default: form)
+	PanelWidth    string        // sm | md | lg (default: sm for notification, md otherwise)
+	Header        bool          // Renders the header with title and close button
+	Title         string        // Header title
+	HeaderActions template.HTML // Extra header actions
+	Content       template.HTML // Sheet content
+	Footer        template.HTML // Footer (hidden when empty)
+}
+
+// SheetFormProps holds the props of the "sheet-form" component
+type SheetFormProps struct {
+	CommonLabels CommonLabels // Labels for the default title
+	Title        string       // Sheet title (default: Add / Edit button label)
+	IsEdit       bool         // Edit mode
+}
+
+// SheetFormFooterProps holds the props of the "sheet-form-footer" component
+type SheetFormFooterProps struct {
+	CommonLabels CommonLabels // Labels for the default button text
+	ShowCancel   bool         // Shows the cancel button
+	IsEdit       bool         // Uses the Update label for submit
+	SubmitLabel  string       // Custom submit label
+	CancelLabel  string       // Custom cancel label
+}
+
+// SkeletonProps holds the props of the "skeleton" components
+type SkeletonProps struct {
+	Variant string // text | circle | rect (default: text)
+	Lines   int    // Text lines 1-10 (default: 1)
+	Width   string // CSS width for rect (default: 100%)
+	Height  string // CSS height for rect (default: 1rem)
+	Size    string // sm | md | lg for circle (default: md)
+	Rows    int    // For "skeleton-table": rows 1-8 (default: 5)
+	Cols    int    // For "skeleton-table": columns 1-6 (default: 4)
+	Class   string // Additional CSS classes
+	ID      string // Element ID
+}
+
+// SidebarCollapsedCookie is the cookie sidebar.js stores the collapsed (icons only) state in
+const SidebarCollapsedCookie = "sidebar-collapsed"
+
+// SidebarProps holds the props of the "sidebar" component
+type SidebarProps struct {
+	ID        string            // Element ID (default: sidebar)
+	Nav       Nav               // Navigation tree with the active items marked (see Nav.ForPage)
+	Apps      []SidebarApp      // App switcher entries (hidden when empty)
+	User      *SidebarUser      // Signed-in user for the user card (hidden when nil)
+	UserMenu  []SidebarMenuItem // User menu entries (see NewUserMenu)
+	Labels    SidebarLabels     // Sidebar labels (CommonLabels.Sidebar)
+	Collapsed bool              // Render collapsed to icons (see IsSidebarCollapsed)
+}
+
+// SidebarApp is an entry of the sidebar's app switcher
+type SidebarApp struct {
+	Key    string // Unique identifier
+	Label  string // App name
+	Href   string // App URL
+	Icon   string // Icon name
+	Active bool   // The current app (shown on the switcher button)
+}
+
+// SidebarUser is the signed-in user shown in the sidebar's user card
+type SidebarUser struct {
+	Name   string      // Display name
+	Email  string      // Shown below the name when there is no Plan
+	Plan   string      // Plan or role shown below the name (e.g., SidebarLabels.UserCard.ProPlan)
+	Avatar AvatarProps // Avatar (see NewSidebarUser)
+}
+
+// SidebarMenuItem is an entry of the sidebar's user menu
+type SidebarMenuItem struct {
+	Label  string // Display text
+	Href   string // Link URL, or form action for post items
+	Icon   string // Icon name
+	Method string // "post" submits a form with the request's csrfToken as csrf_token (e.g., logout)
+}
+
+// NewSidebarProps creates sidebar props for a page, marking the items matching
+// page.ActiveNav and page.ActiveSubNav as active
+func NewSidebarProps(nav Nav, page PageData) SidebarProps {
+	return SidebarProps{Nav: nav.ForPage(page)}
+}
+
+// NewSidebarUser creates a sidebar user with a small initials avatar
+func NewSidebarUser(name, email, avatarURL string) *SidebarUser {
+	avatar := NewAvatarProps(name, avatarURL)
+	avatar.Size = "sm"
+	return &SidebarUser{Name: name, Email: email, Avatar: avatar}
+}
+
+// NewUserMenu creates the standard user menu: profile and billing links (skipped when
+// their URL is empty) and a logout form posted to logoutURL
+func NewUserMenu(labels UserMenuLabels, profileURL, billingURL, logoutURL string) []SidebarMenuItem {
+	var items []SidebarMenuItem
+	if profileURL != "" {
+		items = append(items, SidebarMenuItem{Label: labels.Profile, Href: profileURL, Icon: "user"})
+	}
+	if billingURL != "" {
+		items = append(items, SidebarMenuItem{Label: labels.Billing, Href: billingURL, Icon: "credit-card"})
+	}
+	if logoutURL != "" {
+		items = append(items, SidebarMenuItem{Label: labels.Logout, Href: logoutURL, Icon: "log-out", Method: "post"})
+	}
+	return items
+}
+
+// IsSidebarCollapsed reports whether the user collapsed the sidebar (SidebarCollapsedCookie),
+// so pages render it collapsed without a flash:
+//
+//	props.Collapsed = ui.IsSidebarCollapsed(r)
+func IsSidebarCollapsed(r *http.Request) bool {
+	cookie, err := r.Cookie(SidebarCollapsedCookie)
+	return err == nil && cookie.Value == "1"
+}
+
+// SidebarToggleProps holds the props of the "sidebar-toggle" component
+type SidebarToggleProps struct {
+	ID    string // ID of the sidebar it opens (default: sidebar)
+	Label string // Accessible label (default: "Open menu")
+}
+
+// SpinnerProps holds the props of the "spinner" and "spinner-indicator" components
+type SpinnerProps struct {
+	Size     string // sm | md | lg (default: md)
+	Label    string // Text beside/below the spinner
+	Inline   bool   // inline-flex for use inside text
+	Centered bool   // Centered in the parent container
+	Color    string // primary | muted | inverse (default: primary)
+	Class    string // Additional CSS classes
+	ID       string // Element ID
+}
+
+// StatCardProps holds the props of the "stat-card" and "stat-mini" components
+type StatCardProps struct {
+	Icon    string // Icon name
+	Value   string // Metric value
+	Label   string // Metric label
+	Trend   string // Trend text (e.g., "+12%"); hidden when empty
+	TrendUp bool   // Trend direction
+	Color   string // terracotta | sage | navy | amber
+}
+
+// NewStatCardProps creates stat card props
+func NewStatCardProps(icon, value, label, color string) StatCardProps {
+	return StatCardProps{Icon: icon, Value: value, Label: label, Color: color}
+}
+
+// TabsProps holds the props of the "tabs" component
+type TabsProps struct {
+	Items     []TabItem // Tab items
+	ActiveTab string    // Key of the active tab
+	Variant   string    // default | main | sub | client | help | category (default: default)
+	ID        string    // Container ID
+}
+
+// NewTabsProps creates tabs props
+func NewTabsProps(items []TabItem, activeTab, variant string) TabsProps {
+	return TabsProps{Items: items, ActiveTab: activeTab, Variant: variant}
+}
+
+// ToastProps holds the props of the "toast" and "toast-oob" components
+type ToastProps struct {
+	Message     string // Toast body text (required)
+	State       string // success | error | warning | info (default: info)
+	Title       string // Bold heading above the message
+	Duration    string // Auto-close time in ms (default: 4000); "0" disables auto-close
+	Delay       string // Delay before showing in ms (default: 0)
+	ID          string // Element ID
+	Class       string // Additional CSS classes
+	Dismissible bool   // Shows close button (true in NewToastProps)
+}
+
+// NewToastProps creates dismissible toast props
+func NewToastProps(state, message string) ToastProps {
+	return ToastProps{State: state, Message: message, Dismissible: true}
+}
+
+// ToggleProps holds the props of the "toggle" and "toggle-item" components
+type ToggleProps struct {
+	Name          string // Input name
+	ID            string // Element ID (defaults to Name)
+	Label         string // Label text
+	Description   string // For "toggle-item": text below the label
+	Value         string // Checkbox value (default: 1)
+	Checked       bool   // Checked state
+	Disabled      bool   // Disables the toggle
+	Size          string // small (default: regular)
+	LabelPosition string // left | right (default: left)
+	Class         string // Additional CSS classes
+}
+
+// NewToggleProps creates toggle props
+func NewToggleProps(name, label string, checked bool) ToggleProps {
+	return ToggleProps{Name: name, Label: label, Checked: checked}
+}
+
+// initials returns the uppercased first letters of the first and last words of name
+func initials(name string) string {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return ""
+	}
+	first, _ := utf8.DecodeRuneInString(words[0])
+	result := string(unicode.ToUpper(first))
+	if len(words) > 1 {
+		last, _ := utf8.DecodeRuneInString(words[len(words)-1])
+		result += string(unicode.ToUpper(last))
+	}
+	return result
+}
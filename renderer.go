@@ -1,29 +1,47 @@
 package ui
 
 import (
+	"bytes"
 	"fmt"
 	"html/template"
+	"io/fs"
 	"log"
 	"net/http"
-	"os"
 	"path/filepath"
-	"runtime"
 	"strings"
 	"sync"
 )
 
 // HTMLRenderer handles HTML template rendering with shared components
 type HTMLRenderer struct {
-	templates      *template.Template
-	templateFuncs  template.FuncMap
+	templates        *template.Template
+	pages            map[string]*template.Template // isolated per-page sets (see WithPages)
+	templateFuncs    template.FuncMap
 	templatePatterns []string
-	parseOnce      sync.Once
-	parseErr       error
+	pagesDir         string        // root directory of page templates ("" = no pages)
+	layoutName       string        // layout template executed for pages
+	sharedFS         fs.FS         // optional override for shared components (nil = SourceFS())
+	sharedOptions    SharedOptions // shared source configuration (see WithSharedOptions)
+	parseOnce        sync.Once
+	parseErr         error
+	mu               sync.RWMutex          // guards templates and pages (swapped on reload in dev mode)
+	dev              *devWatcher           // non-nil when dev mode is enabled (see WithDevMode)
+	errorHandler     ErrorHandler          // optional handler for render failures (see WithErrorHandler)
+	strictValidation bool                  // fail Init on template validation issues (see WithStrictValidation)
+	requestBinders   []RequestFuncBinder   // per-request funcs (see WithRequestFuncs)
+	scopes           map[string]*scopePool // copies for request-scoped rendering, keyed by page name ("" = shared set)
+}
+
+// templateSet is the result of parsing: the shared set plus per-page clones
+type templateSet struct {
+	templates *template.Template
+	pages     map[string]*template.Template
+	scopes    map[string]*scopePool
 }
 
 // NewHTMLRenderer creates a new HTMLRenderer.
 // templatePatterns: list of glob patterns for app-specific templates
-// The components package will automatically locate its own templates.
+// Shared components are read from the embedded filesystem (see SourceFS).
 func NewHTMLRenderer(templatePatterns []string) *HTMLRenderer {
 	return &HTMLRenderer{
 		templatePatterns: templatePatterns,
@@ -31,9 +49,20 @@ func NewHTMLRenderer(templatePatterns []string) *HTMLRenderer {
 	}
 }
 
-// WithFuncs adds custom template functions to the renderer
+// WithFuncs adds custom template functions to the renderer.
+// Functions are merged into the defaults; a function with the same name replaces the default.
 func (r *HTMLRenderer) WithFuncs(funcs template.FuncMap) *HTMLRenderer {
-	r.templateFuncs = funcs
+	for name, fn := range funcs {
+		r.templateFuncs[name] = fn
+	}
+	return r
+}
+
+// WithFS sets the filesystem shared components are read from for this renderer.
+// The filesystem must use the package layout (icons/, partials/, components/).
+// Useful during development, e.g. WithFS(os.DirFS("../pyeza-golang")).
+func (r *HTMLRenderer) WithFS(fsys fs.FS) *HTMLRenderer {
+	r.sharedFS = fsys
 	return r
 }
 
@@ -90,100 +119,238 @@ func getDefaultFuncMap() template.FuncMap {
 			}
 			return dict
 		},
+		// asset resolves a package asset to its content-hashed URL
+		// Usage: <script src="{{asset "components/table/table.js"}}"></script>
+		"asset": AssetURL,
+		// icon renders an icon as inline SVG (see Icon for the options)
+		// Usage: {{icon "trash-2" "size" 16 "label" "Delete"}}
+		"icon": Icon,
+		// iconSprite renders a hidden sprite sheet of every icon for {{icon "x" "sprite" true}}
+		// Usage: {{iconSprite}} (once, at the start of <body>)
+		"iconSprite": InlineIconSprite,
+		// assetIntegrity returns a package asset's Subresource Integrity value
+		// Usage: <script src="{{asset "components/sheet.js"}}" integrity="{{assetIntegrity "components/sheet.js"}}"></script>
+		"assetIntegrity": AssetIntegrity,
+		// htmxAsset and htmxConfig describe the HTMX build selected with SetHTMXVersion
+		// Usage: {{template "htmx-script"}} and {{template "htmx-config"}} ({{htmxConfig nonce}})
+		"htmxAsset":  HTMXAsset,
+		"htmxConfig": HTMXConfig,
+		// productionMode reports whether bundled scripts should be loaded
+		// Usage: {{if productionMode}}...{{end}}
+		"productionMode": IsProductionMode,
 		// list creates a slice from values for passing arrays to templates
 		// Usage: {{template "tabs" dict "Items" (list item1 item2 item3)}}
 		"list": func(values ...any) []any {
 			return values
 		},
+		// msg formats an ICU-style message (see FormatMessage), in the request's locale
+		// when rendered with a request
+		// Usage: {{msg "{count, plural, one {# item} other {# items}}" "count" .Total}}
+		"msg": messageFunc(DefaultLocale),
+		// Request-scoped values (see RequestData); empty unless rendered with a request
+		// Usage: <input type="hidden" name="csrf_token" value="{{csrfToken}}">
+		"locale":    func() string { return "" },
+		"user":      func() any { return nil },
+		"csrfToken": func() string { return "" },
+		"nonce":     func() string { return "" },
 	}
 }
 
-// getSharedComponentsDir returns the path to the shared components directory
-func (r *HTMLRenderer) getSharedComponentsDir() string {
-	// Get the file path of this source file
-	_, filename, _, ok := runtime.Caller(0)
-	if !ok {
-		log.Printf("Warning: Could not determine source file location")
+// getSharedFS returns the filesystem holding the shared components.
+// Uses the renderer-specific filesystem if set via WithFS, otherwise SourceFS().
+func (r *HTMLRenderer) getSharedFS() fs.FS {
+	if r.sharedFS != nil {
+		return r.sharedFS
 	}
+	return SourceFS()
+}
+
+// Init parses all templates from the templates directory and validates them
+// (see ValidateTemplates). In dev mode it also starts watching the template sources for changes.
+func (r *HTMLRenderer) Init() error {
+	r.parseOnce.Do(func() {
+		var set *templateSet
+		set, r.parseErr = r.parse()
+		if r.parseErr != nil {
+			return
+		}
+		if r.parseErr = r.checkTemplates(set); r.parseErr != nil {
+			return
+		}
+		r.setTemplates(set)
 
-	// The components directory is the directory containing this source file
-	componentsDir := filepath.Dir(filename)
+		if r.dev != nil {
+			go r.watch()
+		}
+	})
+	return r.parseErr
+}
 
-	// Verify it exists
-	if info, err := os.Stat(componentsDir); err == nil && info.IsDir() {
-		return componentsDir
-	}
+// parse builds a fresh template set: shared components first, then app-specific templates,
+// then one isolated clone per page
+func (r *HTMLRenderer) parse() (*templateSet, error) {
+	// Create template with custom functions
+	tmpl := template.New("").Funcs(r.templateFuncs).Funcs(r.rendererFuncs())
 
-	log.Printf("Warning: Components directory not found at: %s", componentsDir)
+	// Shared sources first (icons, partials, components, then overrides; see WithSharedOptions)
+	for _, source := range r.sharedSources() {
+		files, err := source.files(r.sharedOptions.Exclude)
+		if err != nil {
+			return nil, err
+		}
 
-	// Fallback: use environment variable if set
-	if envDir := os.Getenv("SHARED_COMPONENTS_DIR"); envDir != "" {
-		return envDir
+		if len(files) == 0 {
+			log.Printf("No shared templates found in: %s", source.Name)
+			continue
+		}
+
+		tmpl, err = tmpl.ParseFS(source.FS, files...)
+		if err != nil {
+			log.Printf("Failed to parse shared templates from %s: %v", source.Name, err)
+			return nil, err
+		}
+
+		log.Printf("Parsed %d shared templates from: %s", len(files), source.Name)
 	}
 
-	return componentsDir
-}
+	// Icons registered by the app (see RegisterIcon), unless a source already defines them
+	if err := addIconTemplates(tmpl); err != nil {
+		return nil, err
+	}
 
-// Init parses all templates from the templates directory
-func (r *HTMLRenderer) Init() error {
-	r.parseOnce.Do(func() {
-		// Get shared components directory
-		sharedDir := r.getSharedComponentsDir()
+	// Then app-specific templates (from disk)
+	for _, pattern := range r.templatePatterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
 
-		// Create template with custom functions
-		r.templates = template.New("").Funcs(r.templateFuncs)
+		if len(matches) == 0 {
+			log.Printf("No templates found for pattern: %s", pattern)
+			continue
+		}
 
-		// Build patterns: shared components first, then app-specific templates
-		patterns := []string{
-			filepath.Join(sharedDir, "_icons", "*.html"),
-			filepath.Join(sharedDir, "_partials", "*.html"),
-			filepath.Join(sharedDir, "_components", "*.html"),
+		tmpl, err = tmpl.ParseGlob(pattern)
+		if err != nil {
+			log.Printf("Failed to parse templates for pattern %s: %v", pattern, err)
+			return nil, err
 		}
-		patterns = append(patterns, r.templatePatterns...)
 
-		for _, pattern := range patterns {
-			matches, err := filepath.Glob(pattern)
-			if err != nil {
-				r.parseErr = err
-				return
-			}
+		log.Printf("Parsed %d templates from: %s", len(matches), pattern)
+	}
 
-			if len(matches) == 0 {
-				log.Printf("No templates found for pattern: %s", pattern)
-				continue
-			}
+	// Pages are cloned before the shared set is ever executed (Clone fails afterwards)
+	pages, err := r.parsePages(tmpl)
+	if err != nil {
+		return nil, err
+	}
 
-			r.templates, r.parseErr = r.templates.ParseGlob(pattern)
-			if r.parseErr != nil {
-				log.Printf("Failed to parse templates for pattern %s: %v", pattern, r.parseErr)
-				return
-			}
+	// Likewise the never-executed bases request-scoped rendering copies from
+	scopes, err := newScopePools(tmpl, pages)
+	if err != nil {
+		return nil, err
+	}
 
-			log.Printf("Parsed %d templates from: %s", len(matches), pattern)
-		}
-	})
-	return r.parseErr
+	return &templateSet{templates: tmpl, pages: pages, scopes: scopes}, nil
 }
 
-// Render renders a template with the given data and writes it to the response writer
+// rendererFuncs returns template functions bound to this renderer.
+// They are added after templateFuncs so WithFuncs cannot replace them.
+func (r *HTMLRenderer) rendererFuncs() template.FuncMap {
+	return template.FuncMap{
+		// devReloadURL returns the dev-mode reload event stream URL ("" outside dev mode)
+		// Usage: {{with devReloadURL}}...{{end}} (see the "dev-reload" partial)
+		"devReloadURL": r.devReloadURL,
+	}
+}
+
+// setTemplates swaps in a parsed template set
+func (r *HTMLRenderer) setTemplates(set *templateSet) {
+	r.mu.Lock()
+	r.templates = set.templates
+	r.pages = set.pages
+	r.scopes = set.scopes
+	r.mu.Unlock()
+}
+
+// current returns the active template set, parsing templates on first use
+func (r *HTMLRenderer) current() (*template.Template, error) {
+	if err := r.Init(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.templates, nil
+}
+
+// Render renders a template with the given data and writes it to the response writer.
+// templateName is either a page name (e.g., "clients/list", rendered through its layout)
+// or the name of any shared template (e.g., "table-body-partial").
+//
+// The template is rendered into a buffer first, so a failing template never leaves a
+// half-written response: the error is passed to the error handler (see WithErrorHandler)
+// and returned.
 func (r *HTMLRenderer) Render(w http.ResponseWriter, templateName string, data interface{}) error {
-	if r.templates == nil {
-		if err := r.Init(); err != nil {
-			return err
-		}
+	return r.RenderStatus(w, http.StatusOK, templateName, data)
+}
+
+// RenderStatus renders a template like Render, responding with the given status code
+func (r *HTMLRenderer) RenderStatus(w http.ResponseWriter, status int, templateName string, data interface{}) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if err := r.execute(buf, templateName, data); err != nil {
+		r.handleRenderError(w, err)
+		return err
 	}
+	return writeHTML(w, status, buf)
+}
 
-	tmpl := r.templates.Lookup(templateName)
-	if tmpl == nil {
-		return fmt.Errorf("template not found: %s", templateName)
+// execute renders a page or shared template into buf
+func (r *HTMLRenderer) execute(buf *bytes.Buffer, templateName string, data interface{}) error {
+	tmpl, err := r.lookup(templateName)
+	if err != nil {
+		return err
 	}
+	return tmpl.Execute(buf, data)
+}
 
+// writeHTML writes a rendered body with the status code. A write error comes after
+// the headers were sent, so callers return it without calling the error handler.
+func writeHTML(w http.ResponseWriter, status int, buf *bytes.Buffer) error {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	return tmpl.Execute(w, data)
+	w.WriteHeader(status)
+	if _, err := buf.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+	return nil
+}
+
+// lookup resolves a page name or shared template name to the template to execute
+func (r *HTMLRenderer) lookup(name string) (*template.Template, error) {
+	templates, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	page := r.pages[name]
+	r.mu.RUnlock()
+	if page != nil {
+		return page, nil
+	}
+
+	tmpl := templates.Lookup(name)
+	if tmpl == nil {
+		return nil, fmt.Errorf("template not found: %s", name)
+	}
+	return tmpl, nil
 }
 
 // GetTemplate returns a parsed template by name
 func (r *HTMLRenderer) GetTemplate(name string) *template.Template {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	if r.templates == nil {
 		return nil
 	}
@@ -192,23 +359,23 @@ func (r *HTMLRenderer) GetTemplate(name string) *template.Template {
 
 // GetTemplates returns the underlying template.Template for advanced usage
 func (r *HTMLRenderer) GetTemplates() *template.Template {
-	if r.templates == nil {
-		_ = r.Init()
-	}
-	return r.templates
+	templates, _ := r.current()
+	return templates
 }
 
 // RenderIcon renders an icon template and returns it as HTML
 // iconName is the template name (e.g., "icon-user-check", "icon-award")
 func (r *HTMLRenderer) RenderIcon(iconName string) template.HTML {
-	if r.templates == nil {
-		_ = r.Init()
+	templates, err := r.current()
+	if err != nil {
+		log.Printf("Error rendering icon %s: %v", iconName, err)
+		return template.HTML("")
 	}
 
-	tmpl := r.templates.Lookup(iconName)
+	tmpl := templates.Lookup(iconName)
 	if tmpl == nil {
-		log.Printf("Warning: Icon template not found: %s", iconName)
-		return template.HTML("")
+		// Fall back to the icon library (built-in and registered icons)
+		return Icon(iconName)
 	}
 
 	var buf strings.Builder
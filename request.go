@@ -0,0 +1,220 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+)
+
+// RequestData is per-request context exposed to templates by request-scoped rendering
+// (RenderRequest, HTMXResponse) through the "locale", "user", "csrfToken" and "nonce" funcs.
+type RequestData struct {
+	Locale    string // e.g., "en-AU"
+	User      any    // the signed-in user (app-defined type)
+	CSRFToken string // token for forms and hx-headers
+	Nonce     string // CSP nonce for inline scripts and styles (set by CSP.Middleware)
+}
+
+// requestDataKey is the context key for RequestData
+type requestDataKey struct{}
+
+// WithRequestData returns a copy of ctx carrying data for request-scoped rendering.
+// Typically set by middleware:
+//
+//	ctx := ui.WithRequestData(r.Context(), ui.RequestData{Locale: "en", CSRFToken: token})
+//	next.ServeHTTP(w, r.WithContext(ctx))
+func WithRequestData(ctx context.Context, data RequestData) context.Context {
+	return context.WithValue(ctx, requestDataKey{}, data)
+}
+
+// GetRequestData returns the RequestData stored in ctx (zero value if none)
+func GetRequestData(ctx context.Context) RequestData {
+	data, _ := ctx.Value(requestDataKey{}).(RequestData)
+	return data
+}
+
+// RequestFuncBinder returns the implementations of request-scoped template funcs for a request
+type RequestFuncBinder func(req *http.Request) template.FuncMap
+
+// WithRequestFuncs declares template funcs that depend on the current request.
+// defaults are registered at parse time and used when rendering without a request
+// (Render, RenderStatus); bind returns the per-request implementations, with the same
+// names and signatures, used by RenderRequest and HTMXResponse.Write.
+//
+// Example (translation func bound to the request's locale):
+//
+//	renderer.WithRequestFuncs(
+//	    template.FuncMap{"t": func(key string) string { return key }},
+//	    func(req *http.Request) template.FuncMap {
+//	        tr := translator.For(ui.GetRequestData(req.Context()).Locale)
+//	        return template.FuncMap{"t": tr.T}
+//	    },
+//	)
+func (r *HTMLRenderer) WithRequestFuncs(defaults template.FuncMap, bind RequestFuncBinder) *HTMLRenderer {
+	r.WithFuncs(defaults)
+	if bind != nil {
+		r.requestBinders = append(r.requestBinders, bind)
+	}
+	return r
+}
+
+// RenderRequest renders a template like Render, binding request-scoped funcs for req:
+// the built-in "locale", "user", "csrfToken" and "nonce" (from GetRequestData) and
+// the funcs declared with WithRequestFuncs. Templates are not re-parsed; each render
+// borrows a pooled copy of the template set.
+func (r *HTMLRenderer) RenderRequest(w http.ResponseWriter, req *http.Request, templateName string, data interface{}) error {
+	return r.RenderRequestStatus(w, req, http.StatusOK, templateName, data)
+}
+
+// RenderRequestStatus renders a template like RenderRequest, responding with the given status code
+func (r *HTMLRenderer) RenderRequestStatus(w http.ResponseWriter, req *http.Request, status int, templateName string, data interface{}) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if err := r.executeRequest(buf, req, templateName, data); err != nil {
+		r.handleRenderError(w, err)
+		return err
+	}
+	return writeHTML(w, status, buf)
+}
+
+// requestFuncs returns the request-scoped funcs bound to req
+func (r *HTMLRenderer) requestFuncs(req *http.Request) template.FuncMap {
+	data := GetRequestData(req.Context())
+	funcs := template.FuncMap{
+		"locale":    func() string { return data.Locale },
+		"user":      func() any { return data.User },
+		"csrfToken": func() string { return data.CSRFToken },
+		"nonce":     func() string { return data.Nonce },
+	}
+	if data.Locale != "" {
+		funcs["msg"] = messageFunc(data.Locale)
+	}
+	for _, bind := range r.requestBinders {
+		for name, fn := range bind(req) {
+			funcs[name] = fn
+		}
+	}
+	return funcs
+}
+
+// executeRequest renders a page or shared template with request-scoped funcs bound
+func (r *HTMLRenderer) executeRequest(w io.Writer, req *http.Request, name string, data interface{}) error {
+	if _, err := r.current(); err != nil {
+		return err
+	}
+
+	// Pages are pooled by name (their copies are the layout); anything else is
+	// looked up in a copy of the shared set
+	r.mu.RLock()
+	pool, page := r.scopes[name], true
+	if pool == nil {
+		pool, page = r.scopes[""], false
+	}
+	r.mu.RUnlock()
+
+	if pool == nil {
+		return fmt.Errorf("template not found: %s", name)
+	}
+
+	set, err := pool.get()
+	if err != nil {
+		return err
+	}
+	defer pool.put(set)
+
+	tmpl := set
+	if !page {
+		tmpl = set.Lookup(name)
+	}
+	if tmpl == nil {
+		return fmt.Errorf("template not found: %s", name)
+	}
+
+	bound := r.requestFuncs(req)
+	set.Funcs(bound)
+	// Restore the defaults so pooled copies don't keep the request alive
+	defer set.Funcs(r.defaultFuncs(bound))
+
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return nil
+}
+
+// defaultFuncs returns the parse-time implementations of the named funcs
+func (r *HTMLRenderer) defaultFuncs(names template.FuncMap) template.FuncMap {
+	defaults := make(template.FuncMap, len(names))
+	for name := range names {
+		if fn, ok := r.templateFuncs[name]; ok {
+			defaults[name] = fn
+		}
+	}
+	return defaults
+}
+
+// maxIdleScopes is the number of idle copies a scopePool keeps per set
+const maxIdleScopes = 16
+
+// scopePool hands out copies of a template set for request-scoped rendering.
+// Funcs are shared by every template of a set, so each concurrent render needs its
+// own copy; copies are cloned from a base that is never executed (html/template
+// cannot clone a set after it has executed) and reused, so each copy is escaped once.
+// Idle copies are kept in a bounded free list rather than a sync.Pool, which would
+// drop them (and their escaping work) on every GC.
+type scopePool struct {
+	base *template.Template
+	idle chan *template.Template
+}
+
+// newScopePool creates a pool for set (a page's layout or the shared set).
+// Must be called before set is executed.
+func newScopePool(set *template.Template) (*scopePool, error) {
+	base, err := set.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone templates for request rendering: %w", err)
+	}
+	return &scopePool{base: base, idle: make(chan *template.Template, maxIdleScopes)}, nil
+}
+
+// newScopePools creates the pools of the shared set and every page
+func newScopePools(shared *template.Template, pages map[string]*template.Template) (map[string]*scopePool, error) {
+	scopes := make(map[string]*scopePool, len(pages)+1)
+	for name, page := range pages {
+		pool, err := newScopePool(page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare page %s: %w", name, err)
+		}
+		scopes[name] = pool
+	}
+	pool, err := newScopePool(shared)
+	if err != nil {
+		return nil, err
+	}
+	scopes[""] = pool
+	return scopes, nil
+}
+
+// get returns an idle copy of the set, cloning a new one if there is none
+func (p *scopePool) get() (*template.Template, error) {
+	select {
+	case set := <-p.idle:
+		return set, nil
+	default:
+	}
+	set, err := p.base.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone templates for request rendering: %w", err)
+	}
+	return set, nil
+}
+
+// put returns a copy to the pool, dropping it when the free list is full
+func (p *scopePool) put(set *template.Template) {
+	select {
+	case p.idle <- set:
+	default:
+	}
+}
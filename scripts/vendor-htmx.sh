@@ -0,0 +1,39 @@
+#!/bin/sh
+# Fetches the pinned HTMX releases into assets/vendor/htmx (embedded and served by
+# the asset handler, see vendor.go). Each npm tarball is checked against the
+# registry's published sha512 integrity before its dist/htmx.min.js is copied.
+#
+# Usage (from the repository root):
+#   ./scripts/vendor-htmx.sh
+#
+# Bump the versions together with HTMX1Release / HTMX2Release in vendor.go.
+set -eu
+
+VERSIONS="1.9.12 2.0.4"
+DEST="assets/vendor/htmx"
+TMP=$(mktemp -d)
+trap 'rm -rf "$TMP"' EXIT
+
+mkdir -p "$DEST"
+
+for version in $VERSIONS; do
+    meta=$(curl -fsSL "https://registry.npmjs.org/htmx.org/$version")
+    tarball=$(printf '%s' "$meta" | sed -n 's/.*"tarball":"\([^"]*\)".*/\1/p')
+    expected=$(printf '%s' "$meta" | sed -n 's/.*"integrity":"\(sha512-[^"]*\)".*/\1/p')
+    if [ -z "$tarball" ] || [ -z "$expected" ]; then
+        echo "htmx.org@$version: registry metadata has no tarball or integrity" >&2
+        exit 1
+    fi
+
+    curl -fsSL -o "$TMP/htmx-$version.tgz" "$tarball"
+    actual="sha512-$(openssl dgst -sha512 -binary "$TMP/htmx-$version.tgz" | openssl base64 -A)"
+    if [ "$actual" != "$expected" ]; then
+        echo "htmx.org@$version: integrity mismatch (got $actual, want $expected)" >&2
+        exit 1
+    fi
+
+    tar -xzf "$TMP/htmx-$version.tgz" -C "$TMP" package/dist/htmx.min.js
+    cp "$TMP/package/dist/htmx.min.js" "$DEST/htmx-$version.min.js"
+    rm -rf "$TMP/package"
+    echo "Vendored htmx.org@$version -> $DEST/htmx-$version.min.js"
+done
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+)
+
+// SharedSource is a filesystem and the template globs the renderer reads from it.
+// Sources are parsed in order; a template defined by a later source replaces the
+// same-named template of an earlier one.
+type SharedSource struct {
+	Name     string   // label used in logs (e.g., "components")
+	FS       fs.FS    // filesystem the patterns are relative to
+	Patterns []string // template globs (e.g., "components/*.html")
+}
+
+// SharedOptions configures where the renderer loads shared templates from.
+// The app's templatePatterns are always parsed after every shared source.
+type SharedOptions struct {
+	// Sources replaces the default sources (see DefaultSharedSources) when non-nil.
+	// Use it to reorder or drop shared directories.
+	Sources []SharedSource
+
+	// Overrides are parsed after Sources, so their templates shadow shared ones
+	// with the same name (e.g., an app's own "toast").
+	Overrides []SharedSource
+
+	// Exclude lists shared files to skip, relative to their source
+	// (e.g., "components/toast.html"). Useful when an override replaces a whole file.
+	Exclude []string
+}
+
+// DefaultSharedSources returns the shared sources of the package layout in parse order:
+// icons, then partials, then components (so components can override partial definitions).
+func DefaultSharedSources(fsys fs.FS) []SharedSource {
+	return []SharedSource{
+		{Name: "icons", FS: fsys, Patterns: []string{"icons/*.html"}},
+		{Name: "partials", FS: fsys, Patterns: []string{"partials/*.html"}},
+		{Name: "components", FS: fsys, Patterns: []string{"components/*.html"}},
+	}
+}
+
+// WithSharedOptions configures the shared template sources.
+//
+// Example (shadow the shared toast with the app's own, keep everything else):
+//
+//	renderer.WithSharedOptions(ui.SharedOptions{
+//	    Overrides: []ui.SharedSource{{
+//	        Name:     "app-components",
+//	        FS:       os.DirFS("templates"),
+//	        Patterns: []string{"components/toast.html"},
+//	    }},
+//	})
+func (r *HTMLRenderer) WithSharedOptions(opts SharedOptions) *HTMLRenderer {
+	r.sharedOptions = opts
+	return r
+}
+
+// sharedSources returns the shared sources in parse order
+func (r *HTMLRenderer) sharedSources() []SharedSource {
+	sources := r.sharedOptions.Sources
+	if sources == nil {
+		sources = DefaultSharedSources(r.getSharedFS())
+	}
+	return append(append([]SharedSource(nil), sources...), r.sharedOptions.Overrides...)
+}
+
+// files returns the files matched by the source's patterns, minus excluded ones
+func (s SharedSource) files(exclude []string) ([]string, error) {
+	if s.FS == nil {
+		return nil, fmt.Errorf("shared source %s has no filesystem", s.Name)
+	}
+
+	excluded := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		excluded[path.Clean(name)] = true
+	}
+
+	var files []string
+	for _, pattern := range s.Patterns {
+		matches, err := fs.Glob(s.FS, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s in shared source %s: %w", pattern, s.Name, err)
+		}
+		for _, match := range matches {
+			if !excluded[match] {
+				files = append(files, match)
+			}
+		}
+	}
+	return files, nil
+}
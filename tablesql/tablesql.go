@@ -0,0 +1,409 @@
+// Package tablesql turns a parsed table request (types.TableQuery) into a parameterised
+// SQL fragment: WHERE conditions for the search term and filter conditions, ORDER BY
+// for the sort, and LIMIT/OFFSET for the page. Columns are only ever referenced through
+// a whitelist mapping TableColumn.Key to an SQL expression; values are always passed as
+// placeholder arguments.
+//
+// Example:
+//
+//	clients := tablesql.New(tablesql.Postgres, map[string]string{
+//	    "name":   "c.name",
+//	    "email":  "c.email",
+//	    "status": "c.status",
+//	}).WithSearch("name", "email").WithTieBreaker("c.id")
+//
+//	q, err := types.ParseTableQuery(r, cfg)
+//	...
+//	frag, err := clients.Build(q)
+//	...
+//	where, whereArgs := frag.WhereSQL()
+//	err = db.QueryRow("SELECT count(*) FROM clients c "+where, whereArgs...).Scan(&total)
+//	...
+//	q.ClampPage(total)
+//	frag, err = clients.Build(q) // LIMIT/OFFSET of the clamped page
+//	...
+//	suffix, args := frag.SQL()
+//	rows, err := db.Query("SELECT c.name, c.email, c.status FROM clients c "+suffix, args...)
+package tablesql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"leapfor.xyz/pyeza-golang/types"
+)
+
+// Dialect selects the placeholder style and LIKE operator of the generated SQL
+type Dialect int
+
+const (
+	Postgres Dialect = iota // $1 placeholders, ILIKE
+	MySQL                   // ? placeholders, LIKE (case-insensitive under the default collations)
+	SQLite                  // ? placeholders, LIKE (case-insensitive for ASCII)
+)
+
+// likeEscape is the LIKE escape character. '!' needs no escaping in any dialect's
+// string literals, unlike a backslash.
+const likeEscape = "!"
+
+// Builder builds SQL fragments for one table
+type Builder struct {
+	dialect    Dialect
+	columns    map[string]string
+	search     []string
+	tieBreaker string
+	argOffset  int
+	cursors    *types.CursorCodec
+}
+
+// New creates a builder for a dialect. columns maps each filterable or sortable
+// TableColumn.Key to the SQL expression it reads (e.g., "name" -> "c.name"); keys
+// not in the map are rejected. Map non-text columns to a text expression
+// (e.g., "CAST(c.amount AS TEXT)") if text filters should apply to them.
+func New(dialect Dialect, columns map[string]string) *Builder {
+	return &Builder{dialect: dialect, columns: columns}
+}
+
+// WithSearch sets the column keys the search term is matched against (contains,
+// any column). Without search columns the search term is ignored.
+func (b *Builder) WithSearch(keys ...string) *Builder {
+	b.search = keys
+	return b
+}
+
+// WithTieBreaker appends a unique expression (e.g., the primary key) to every ORDER BY,
+// so rows with equal sort values keep a stable order across pages
+func (b *Builder) WithTieBreaker(expr string) *Builder {
+	b.tieBreaker = expr
+	return b
+}
+
+// WithCursors sets the codec that decodes cursor-mode tokens into keyset predicates.
+// Keyset pagination also needs a tie-breaker (WithTieBreaker), and NULL sort values
+// never compare, so map nullable sort columns to a non-NULL expression, e.g.
+//
+//	"name": "COALESCE(c.name, '')",
+func (b *Builder) WithCursors(codec *types.CursorCodec) *Builder {
+	b.cursors = codec
+	return b
+}
+
+// WithArgOffset numbers Postgres placeholders after n arguments of the surrounding
+// query (e.g., 1 when it already uses $1). Has no effect on ? placeholders.
+func (b *Builder) WithArgOffset(n int) *Builder {
+	b.argOffset = n
+	return b
+}
+
+// Fragment is the SQL built for a table request
+type Fragment struct {
+	Where      string // conditions for a WHERE clause, without the keyword ("" = none)
+	WhereArgs  []any  // arguments of Where's placeholders
+	Keyset     string // cursor mode: condition selecting the rows past the cursor ("" = first page)
+	KeysetArgs []any  // arguments of Keyset's placeholders (numbered after WhereArgs)
+	OrderBy    string // ORDER BY list, without the keyword ("" = unsorted)
+	Limit      string // "LIMIT ... OFFSET ..." ("" when the page size is 0)
+	LimitArgs  []any  // arguments of Limit's placeholders (numbered after KeysetArgs)
+	Reversed   bool   // cursor mode, "prev": rows come back in reverse display order
+}
+
+// SQL returns the full fragment ("WHERE ... ORDER BY ... LIMIT ...") and its arguments
+func (f Fragment) SQL() (string, []any) {
+	var parts []string
+	switch {
+	case f.Where != "" && f.Keyset != "":
+		parts = append(parts, "WHERE "+f.Where+" AND "+f.Keyset)
+	case f.Where != "":
+		parts = append(parts, "WHERE "+f.Where)
+	case f.Keyset != "":
+		parts = append(parts, "WHERE "+f.Keyset)
+	}
+	if f.OrderBy != "" {
+		parts = append(parts, "ORDER BY "+f.OrderBy)
+	}
+	if f.Limit != "" {
+		parts = append(parts, f.Limit)
+	}
+	args := make([]any, 0, len(f.WhereArgs)+len(f.KeysetArgs)+len(f.LimitArgs))
+	args = append(args, f.WhereArgs...)
+	args = append(args, f.KeysetArgs...)
+	args = append(args, f.LimitArgs...)
+	return strings.Join(parts, " "), args
+}
+
+// WhereSQL returns "WHERE ..." ("" when there are no conditions) and its arguments,
+// for the matching COUNT query (without the keyset condition)
+func (f Fragment) WhereSQL() (string, []any) {
+	if f.Where == "" {
+		return "", nil
+	}
+	return "WHERE " + f.Where, f.WhereArgs
+}
+
+// Build translates the search term, filters, sort and page of q. Offset-mode
+// queries get LIMIT and OFFSET. Cursor-mode queries get the keyset condition of
+// q.Cursor (see WithCursors) and a LIMIT of one extra row, which tells whether
+// there is another page; for "prev" the order is reversed so the rows nearest the
+// cursor come first (Fragment.Reversed). A column key that is not in the builder's
+// mapping, or an invalid cursor, returns an error wrapping types.ErrInvalidTableQuery.
+//
+// Cursor mode, end to end:
+//
+//	frag, err := clients.Build(q)
+//	...
+//	rows := query(frag.SQL())
+//	hasMore := len(rows) > q.PageSize
+//	rows = rows[:min(len(rows), q.PageSize)]
+//	if frag.Reversed {
+//	    slices.Reverse(rows)
+//	}
+//	err = cursors.Paginate(sp, q, key(rows[0]), key(rows[len(rows)-1]), hasMore)
+func (b *Builder) Build(q types.TableQuery) (Fragment, error) {
+	w := &writer{dialect: b.dialect, next: b.argOffset + 1}
+	var f Fragment
+
+	var conditions []string
+	if search, err := b.searchCondition(w, q.Search); err != nil {
+		return Fragment{}, err
+	} else if search != "" {
+		conditions = append(conditions, search)
+	}
+	if filters, err := b.filterCondition(w, q.Filters); err != nil {
+		return Fragment{}, err
+	} else if filters != "" {
+		conditions = append(conditions, filters)
+	}
+	f.Where = strings.Join(conditions, " AND ")
+	f.WhereArgs = w.args
+
+	direction := q.SortDirection
+	if q.Mode == "cursor" && q.Cursor != "" {
+		w.args = nil
+		keyset, err := b.keyset(w, q)
+		if err != nil {
+			return Fragment{}, err
+		}
+		f.Keyset = keyset
+		f.KeysetArgs = w.args
+		if q.CursorDirection == "prev" {
+			f.Reversed = true
+			direction = reverseDirection(direction)
+		}
+	}
+
+	orderBy, err := b.orderBy(q.SortColumn, direction)
+	if err != nil {
+		return Fragment{}, err
+	}
+	f.OrderBy = orderBy
+
+	if q.PageSize > 0 {
+		w.args = nil
+		if q.Mode == "cursor" {
+			f.Limit = "LIMIT " + w.arg(q.PageSize+1)
+		} else {
+			f.Limit = "LIMIT " + w.arg(q.PageSize) + " OFFSET " + w.arg(q.Offset())
+		}
+		f.LimitArgs = w.args
+	}
+	return f, nil
+}
+
+// keyset builds the condition selecting the rows past q's cursor in its direction:
+// (a > $1 OR (a = $2 AND id > $3)), with < for descending order or "prev"
+func (b *Builder) keyset(w *writer, q types.TableQuery) (string, error) {
+	if b.cursors == nil {
+		return "", fmt.Errorf("%w: no cursor codec for cursor mode (see WithCursors)", types.ErrInvalidTableQuery)
+	}
+	if b.tieBreaker == "" {
+		return "", fmt.Errorf("%w: no tie-breaker for cursor mode (see WithTieBreaker)", types.ErrInvalidTableQuery)
+	}
+	cur, err := b.cursors.DecodeQuery(q)
+	if err != nil {
+		return "", err
+	}
+
+	var exprs []string
+	if q.SortColumn != "" {
+		expr, err := b.column(q.SortColumn)
+		if err != nil {
+			return "", err
+		}
+		exprs = append(exprs, expr)
+	}
+	if len(cur.Values) != len(exprs) {
+		return "", fmt.Errorf("%w: %d sort values for %d sort columns", types.ErrInvalidCursor, len(cur.Values), len(exprs))
+	}
+	exprs = append(exprs, b.tieBreaker)
+	values := append(cur.Values[:len(cur.Values):len(cur.Values)], cur.ID)
+
+	op := ">"
+	if (q.SortDirection == "desc") != (q.CursorDirection == "prev") {
+		op = "<"
+	}
+	var alternatives []string
+	for i := range exprs {
+		var terms []string
+		for j := 0; j < i; j++ {
+			terms = append(terms, exprs[j]+" = "+w.arg(values[j]))
+		}
+		terms = append(terms, exprs[i]+" "+op+" "+w.arg(values[i]))
+		if len(terms) == 1 {
+			alternatives = append(alternatives, terms[0])
+		} else {
+			alternatives = append(alternatives, "("+strings.Join(terms, " AND ")+")")
+		}
+	}
+	if len(alternatives) == 1 {
+		return alternatives[0], nil
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", nil
+}
+
+// Condition translates a single filter condition (e.g., for custom WHERE clauses)
+func (b *Builder) Condition(c types.FilterCondition) (string, []any, error) {
+	w := &writer{dialect: b.dialect, next: b.argOffset + 1}
+	sql, err := b.condition(w, c)
+	return sql, w.args, err
+}
+
+// searchCondition matches the search term against every search column
+func (b *Builder) searchCondition(w *writer, search string) (string, error) {
+	if search == "" || len(b.search) == 0 {
+		return "", nil
+	}
+	var matches []string
+	for _, key := range b.search {
+		expr, err := b.column(key)
+		if err != nil {
+			return "", err
+		}
+		matches = append(matches, w.like(expr, "%"+escapeLike(search)+"%"))
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	return "(" + strings.Join(matches, " OR ") + ")", nil
+}
+
+// filterCondition joins the filter conditions left to right, as the client-side
+// filter does: ((c1 AND c2) OR c3)
+func (b *Builder) filterCondition(w *writer, filters []types.FilterCondition) (string, error) {
+	var sql string
+	for i, c := range filters {
+		cond, err := b.condition(w, c)
+		if err != nil {
+			return "", err
+		}
+		if i == 0 {
+			sql = cond
+			continue
+		}
+		op := " AND "
+		if c.Logic == "or" {
+			op = " OR "
+		}
+		sql = "(" + sql + op + cond + ")"
+	}
+	return sql, nil
+}
+
+// condition translates one filter condition. Comparisons are case-insensitive and a
+// NULL column counts as empty, matching the client-side filter.
+func (b *Builder) condition(w *writer, c types.FilterCondition) (string, error) {
+	expr, err := b.column(c.Column)
+	if err != nil {
+		return "", err
+	}
+	switch c.Operator {
+	case types.FilterContains:
+		return w.like(expr, "%"+escapeLike(c.Value)+"%"), nil
+	case types.FilterStartsWith:
+		return w.like(expr, escapeLike(c.Value)+"%"), nil
+	case types.FilterEndsWith:
+		return w.like(expr, "%"+escapeLike(c.Value)), nil
+	case types.FilterEquals:
+		return "LOWER(" + expr + ") = LOWER(" + w.arg(c.Value) + ")", nil
+	case types.FilterNotEquals:
+		return "(" + expr + " IS NULL OR LOWER(" + expr + ") <> LOWER(" + w.arg(c.Value) + "))", nil
+	case types.FilterIsEmpty:
+		return "(" + expr + " IS NULL OR " + expr + " = '')", nil
+	case types.FilterIsNotEmpty:
+		return "(" + expr + " IS NOT NULL AND " + expr + " <> '')", nil
+	}
+	return "", fmt.Errorf("%w: filter operator %q", types.ErrInvalidTableQuery, c.Operator)
+}
+
+// orderBy builds the ORDER BY list for a sort column and direction
+func (b *Builder) orderBy(key, direction string) (string, error) {
+	var order []string
+	if key != "" {
+		expr, err := b.column(key)
+		if err != nil {
+			return "", err
+		}
+		order = append(order, expr+" "+sqlDirection(direction))
+	}
+	if b.tieBreaker != "" {
+		order = append(order, b.tieBreaker+" "+sqlDirection(direction))
+	}
+	return strings.Join(order, ", "), nil
+}
+
+// column returns the SQL expression mapped to a column key
+func (b *Builder) column(key string) (string, error) {
+	expr, ok := b.columns[key]
+	if !ok || expr == "" {
+		return "", fmt.Errorf("%w: column %q is not mapped to SQL", types.ErrInvalidTableQuery, key)
+	}
+	return expr, nil
+}
+
+// sqlDirection returns ASC or DESC for a sort direction
+func sqlDirection(direction string) string {
+	if direction == "desc" {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// reverseDirection returns the opposite sort direction
+func reverseDirection(direction string) string {
+	if direction == "desc" {
+		return "asc"
+	}
+	return "desc"
+}
+
+// escapeLike escapes LIKE wildcards (and the escape character) in a value
+func escapeLike(value string) string {
+	return strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_").Replace(value)
+}
+
+// writer collects placeholder arguments in order
+type writer struct {
+	dialect Dialect
+	next    int // next Postgres placeholder number
+	args    []any
+}
+
+// arg adds an argument and returns its placeholder
+func (w *writer) arg(value any) string {
+	w.args = append(w.args, value)
+	if w.dialect == Postgres {
+		p := "$" + strconv.Itoa(w.next)
+		w.next++
+		return p
+	}
+	return "?"
+}
+
+// like returns a case-insensitive LIKE match of expr against pattern
+func (w *writer) like(expr, pattern string) string {
+	op := "LIKE"
+	if w.dialect == Postgres {
+		op = "ILIKE"
+	}
+	return expr + " " + op + " " + w.arg(pattern) + " ESCAPE '" + likeEscape + "'"
+}
@@ -0,0 +1,417 @@
+package tablesql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"leapfor.xyz/pyeza-golang/types"
+)
+
+// testColumns is the column mapping of the test builders
+var testColumns = map[string]string{
+	"name":   "c.name",
+	"email":  "c.email",
+	"status": "c.status",
+}
+
+func TestBuild(t *testing.T) {
+	query := types.TableQuery{
+		Mode:          "offset",
+		Page:          3,
+		PageSize:      20,
+		Search:        "ann",
+		SortColumn:    "name",
+		SortDirection: "desc",
+		Filters:       []types.FilterCondition{{Column: "status", Operator: types.FilterEquals, Value: "Active"}},
+	}
+	searchArgs := []any{"%ann%", "%ann%", "Active", 20, 40}
+
+	tests := []struct {
+		name      string
+		dialect   Dialect
+		argOffset int
+		q         types.TableQuery
+		want      string
+		wantArgs  []any
+	}{
+		{
+			name:     "postgres",
+			dialect:  Postgres,
+			q:        query,
+			want:     "WHERE (c.name ILIKE $1 ESCAPE '!' OR c.email ILIKE $2 ESCAPE '!') AND LOWER(c.status) = LOWER($3) ORDER BY c.name DESC, c.id DESC LIMIT $4 OFFSET $5",
+			wantArgs: searchArgs,
+		},
+		{
+			name:     "mysql",
+			dialect:  MySQL,
+			q:        query,
+			want:     "WHERE (c.name LIKE ? ESCAPE '!' OR c.email LIKE ? ESCAPE '!') AND LOWER(c.status) = LOWER(?) ORDER BY c.name DESC, c.id DESC LIMIT ? OFFSET ?",
+			wantArgs: searchArgs,
+		},
+		{
+			name:     "sqlite",
+			dialect:  SQLite,
+			q:        query,
+			want:     "WHERE (c.name LIKE ? ESCAPE '!' OR c.email LIKE ? ESCAPE '!') AND LOWER(c.status) = LOWER(?) ORDER BY c.name DESC, c.id DESC LIMIT ? OFFSET ?",
+			wantArgs: searchArgs,
+		},
+		{
+			name:      "postgres with arg offset",
+			dialect:   Postgres,
+			argOffset: 2,
+			q:         query,
+			want:      "WHERE (c.name ILIKE $3 ESCAPE '!' OR c.email ILIKE $4 ESCAPE '!') AND LOWER(c.status) = LOWER($5) ORDER BY c.name DESC, c.id DESC LIMIT $6 OFFSET $7",
+			wantArgs:  searchArgs,
+		},
+		{
+			name:      "arg offset has no effect on ? placeholders",
+			dialect:   MySQL,
+			argOffset: 2,
+			q:         types.TableQuery{Mode: "offset", Page: 1, PageSize: 10, Search: "ann"},
+			want:      "WHERE (c.name LIKE ? ESCAPE '!' OR c.email LIKE ? ESCAPE '!') ORDER BY c.id ASC LIMIT ? OFFSET ?",
+			wantArgs:  []any{"%ann%", "%ann%", 10, 0},
+		},
+		{
+			name:     "no conditions, unsorted, no page size",
+			dialect:  Postgres,
+			q:        types.TableQuery{Mode: "offset", Page: 1},
+			want:     "ORDER BY c.id ASC",
+			wantArgs: []any{},
+		},
+		{
+			name:    "like wildcards and the escape character are escaped",
+			dialect: Postgres,
+			q: types.TableQuery{Filters: []types.FilterCondition{
+				{Column: "name", Operator: types.FilterContains, Value: "50%_off!"},
+			}},
+			want:     "WHERE c.name ILIKE $1 ESCAPE '!' ORDER BY c.id ASC",
+			wantArgs: []any{"%50!%!_off!!%"},
+		},
+		{
+			name:    "filters fold left to right",
+			dialect: SQLite,
+			q: types.TableQuery{Filters: []types.FilterCondition{
+				{Column: "name", Operator: types.FilterStartsWith, Value: "a"},
+				{Column: "email", Operator: types.FilterEndsWith, Value: ".org", Logic: "and"},
+				{Column: "status", Operator: types.FilterIsEmpty, Logic: "or"},
+			}},
+			want:     "WHERE ((c.name LIKE ? ESCAPE '!' AND c.email LIKE ? ESCAPE '!') OR (c.status IS NULL OR c.status = '')) ORDER BY c.id ASC",
+			wantArgs: []any{"a%", "%.org"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.dialect, testColumns).WithSearch("name", "email").WithTieBreaker("c.id").WithArgOffset(tt.argOffset)
+			frag, err := b.Build(tt.q)
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			sql, args := frag.SQL()
+			if sql != tt.want {
+				t.Errorf("SQL:\n got %s\nwant %s", sql, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestFragmentWhereSQL(t *testing.T) {
+	b := New(Postgres, testColumns).WithSearch("name").WithTieBreaker("c.id")
+	frag, err := b.Build(types.TableQuery{Mode: "offset", Page: 2, PageSize: 10, Search: "ann"})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	where, args := frag.WhereSQL()
+	if want := "WHERE c.name ILIKE $1 ESCAPE '!'"; where != want {
+		t.Errorf("WhereSQL = %q, want %q", where, want)
+	}
+	if want := []any{"%ann%"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+
+	frag, err = b.Build(types.TableQuery{Mode: "offset", Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if where, args := frag.WhereSQL(); where != "" || len(args) != 0 {
+		t.Errorf("WhereSQL = %q %v, want no conditions", where, args)
+	}
+}
+
+func TestCondition(t *testing.T) {
+	tests := []struct {
+		operator string
+		want     string
+		wantArgs []any
+	}{
+		{types.FilterContains, "c.name ILIKE $1 ESCAPE '!'", []any{"%Ann%"}},
+		{types.FilterStartsWith, "c.name ILIKE $1 ESCAPE '!'", []any{"Ann%"}},
+		{types.FilterEndsWith, "c.name ILIKE $1 ESCAPE '!'", []any{"%Ann"}},
+		{types.FilterEquals, "LOWER(c.name) = LOWER($1)", []any{"Ann"}},
+		{types.FilterNotEquals, "(c.name IS NULL OR LOWER(c.name) <> LOWER($1))", []any{"Ann"}},
+		{types.FilterIsEmpty, "(c.name IS NULL OR c.name = '')", nil},
+		{types.FilterIsNotEmpty, "(c.name IS NOT NULL AND c.name <> '')", nil},
+	}
+
+	b := New(Postgres, testColumns)
+	for _, tt := range tests {
+		t.Run(tt.operator, func(t *testing.T) {
+			sql, args, err := b.Condition(types.FilterCondition{Column: "name", Operator: tt.operator, Value: "Ann"})
+			if err != nil {
+				t.Fatalf("Condition: %v", err)
+			}
+			if sql != tt.want {
+				t.Errorf("SQL = %q, want %q", sql, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		search []string
+		q      types.TableQuery
+	}{
+		{
+			name: "unmapped sort column",
+			q:    types.TableQuery{SortColumn: "password", SortDirection: "asc"},
+		},
+		{
+			name: "unmapped filter column",
+			q:    types.TableQuery{Filters: []types.FilterCondition{{Column: "password", Operator: types.FilterEquals, Value: "x"}}},
+		},
+		{
+			name:   "unmapped search column",
+			search: []string{"name", "password"},
+			q:      types.TableQuery{Search: "ann"},
+		},
+		{
+			name: "unknown operator",
+			q:    types.TableQuery{Filters: []types.FilterCondition{{Column: "name", Operator: "matches", Value: "x"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(Postgres, testColumns).WithSearch(tt.search...)
+			_, err := b.Build(tt.q)
+			if !errors.Is(err, types.ErrInvalidTableQuery) {
+				t.Errorf("Build error = %v, want ErrInvalidTableQuery", err)
+			}
+		})
+	}
+}
+
+// testCursorKey is the HMAC key of the test cursor codecs
+var testCursorKey = []byte("0123456789abcdef0123456789abcdef")
+
+// newTestCodec creates a cursor codec, failing the test on an error
+func newTestCodec(t *testing.T, key []byte) *types.CursorCodec {
+	t.Helper()
+	codec, err := types.NewCursorCodec(key)
+	if err != nil {
+		t.Fatalf("NewCursorCodec: %v", err)
+	}
+	return codec
+}
+
+func TestBuildKeyset(t *testing.T) {
+	codec := newTestCodec(t, testCursorKey)
+	token := func(sort, direction string, values ...any) string {
+		t.Helper()
+		token, err := codec.Encode(types.Cursor{Sort: sort, Direction: direction, Values: values, ID: int64(7)})
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		return token
+	}
+	cursorQuery := func(sort, direction, curdir string, values ...any) types.TableQuery {
+		return types.TableQuery{
+			Mode:            "cursor",
+			PageSize:        20,
+			Cursor:          token(sort, direction, values...),
+			CursorDirection: curdir,
+			SortColumn:      sort,
+			SortDirection:   direction,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		dialect      Dialect
+		argOffset    int
+		q            types.TableQuery
+		want         string
+		wantArgs     []any
+		wantReversed bool
+	}{
+		{
+			name:     "asc next",
+			dialect:  Postgres,
+			q:        cursorQuery("name", "asc", "next", "Bob"),
+			want:     "WHERE (c.name > $1 OR (c.name = $2 AND c.id > $3)) ORDER BY c.name ASC, c.id ASC LIMIT $4",
+			wantArgs: []any{"Bob", "Bob", int64(7), 21},
+		},
+		{
+			name:         "asc prev",
+			dialect:      Postgres,
+			q:            cursorQuery("name", "asc", "prev", "Bob"),
+			want:         "WHERE (c.name < $1 OR (c.name = $2 AND c.id < $3)) ORDER BY c.name DESC, c.id DESC LIMIT $4",
+			wantArgs:     []any{"Bob", "Bob", int64(7), 21},
+			wantReversed: true,
+		},
+		{
+			name:     "desc next",
+			dialect:  Postgres,
+			q:        cursorQuery("name", "desc", "next", "Bob"),
+			want:     "WHERE (c.name < $1 OR (c.name = $2 AND c.id < $3)) ORDER BY c.name DESC, c.id DESC LIMIT $4",
+			wantArgs: []any{"Bob", "Bob", int64(7), 21},
+		},
+		{
+			name:         "desc prev",
+			dialect:      Postgres,
+			q:            cursorQuery("name", "desc", "prev", "Bob"),
+			want:         "WHERE (c.name > $1 OR (c.name = $2 AND c.id > $3)) ORDER BY c.name ASC, c.id ASC LIMIT $4",
+			wantArgs:     []any{"Bob", "Bob", int64(7), 21},
+			wantReversed: true,
+		},
+		{
+			name:     "unsorted",
+			dialect:  Postgres,
+			q:        cursorQuery("", "asc", "next"),
+			want:     "WHERE c.id > $1 ORDER BY c.id ASC LIMIT $2",
+			wantArgs: []any{int64(7), 21},
+		},
+		{
+			name:      "numbered after the conditions and the arg offset",
+			dialect:   Postgres,
+			argOffset: 1,
+			q: func() types.TableQuery {
+				q := cursorQuery("name", "asc", "next", "Bob")
+				q.Search = "b"
+				return q
+			}(),
+			want:     "WHERE c.name ILIKE $2 ESCAPE '!' AND (c.name > $3 OR (c.name = $4 AND c.id > $5)) ORDER BY c.name ASC, c.id ASC LIMIT $6",
+			wantArgs: []any{"%b%", "Bob", "Bob", int64(7), 21},
+		},
+		{
+			name:    "mysql",
+			dialect: MySQL,
+			q: func() types.TableQuery {
+				q := cursorQuery("name", "asc", "next", "Bob")
+				q.Search = "b"
+				return q
+			}(),
+			want:     "WHERE c.name LIKE ? ESCAPE '!' AND (c.name > ? OR (c.name = ? AND c.id > ?)) ORDER BY c.name ASC, c.id ASC LIMIT ?",
+			wantArgs: []any{"%b%", "Bob", "Bob", int64(7), 21},
+		},
+		{
+			name:     "first page",
+			dialect:  SQLite,
+			q:        types.TableQuery{Mode: "cursor", PageSize: 20, SortColumn: "name", SortDirection: "asc"},
+			want:     "ORDER BY c.name ASC, c.id ASC LIMIT ?",
+			wantArgs: []any{21},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.dialect, testColumns).WithSearch("name").WithTieBreaker("c.id").WithCursors(codec).WithArgOffset(tt.argOffset)
+			frag, err := b.Build(tt.q)
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			sql, args := frag.SQL()
+			if sql != tt.want {
+				t.Errorf("SQL:\n got %s\nwant %s", sql, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+			if frag.Reversed != tt.wantReversed {
+				t.Errorf("Reversed = %v, want %v", frag.Reversed, tt.wantReversed)
+			}
+		})
+	}
+}
+
+func TestBuildKeysetErrors(t *testing.T) {
+	codec := newTestCodec(t, testCursorKey)
+	token, err := codec.Encode(types.Cursor{Sort: "name", Direction: "asc", Values: []any{"Bob"}, ID: int64(7)})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	unsorted, err := codec.Encode(types.Cursor{Sort: "name", Direction: "asc", ID: int64(7)})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	forged, err := newTestCodec(t, []byte("another-key-another-key-another-k")).Encode(types.Cursor{Sort: "name", Direction: "asc", Values: []any{"Bob"}, ID: int64(7)})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	query := func(token, sort, direction string) types.TableQuery {
+		return types.TableQuery{Mode: "cursor", PageSize: 20, Cursor: token, CursorDirection: "next", SortColumn: sort, SortDirection: direction}
+	}
+
+	tests := []struct {
+		name    string
+		builder *Builder
+		q       types.TableQuery
+		cursor  bool // the error also wraps ErrInvalidCursor
+	}{
+		{
+			name:    "no codec",
+			builder: New(Postgres, testColumns).WithTieBreaker("c.id"),
+			q:       query(token, "name", "asc"),
+		},
+		{
+			name:    "no tie-breaker",
+			builder: New(Postgres, testColumns).WithCursors(codec),
+			q:       query(token, "name", "asc"),
+		},
+		{
+			name:    "cursor for another sort column",
+			builder: New(Postgres, testColumns).WithTieBreaker("c.id").WithCursors(codec),
+			q:       query(token, "email", "asc"),
+			cursor:  true,
+		},
+		{
+			name:    "cursor for another direction",
+			builder: New(Postgres, testColumns).WithTieBreaker("c.id").WithCursors(codec),
+			q:       query(token, "name", "desc"),
+			cursor:  true,
+		},
+		{
+			name:    "forged cursor",
+			builder: New(Postgres, testColumns).WithTieBreaker("c.id").WithCursors(codec),
+			q:       query(forged, "name", "asc"),
+			cursor:  true,
+		},
+		{
+			name:    "sort values missing",
+			builder: New(Postgres, testColumns).WithTieBreaker("c.id").WithCursors(codec),
+			q:       query(unsorted, "name", "asc"),
+			cursor:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build(tt.q)
+			if !errors.Is(err, types.ErrInvalidTableQuery) {
+				t.Fatalf("Build error = %v, want ErrInvalidTableQuery", err)
+			}
+			if errors.Is(err, types.ErrInvalidCursor) != tt.cursor {
+				t.Errorf("Build error = %v, wraps ErrInvalidCursor = %v, want %v", err, !tt.cursor, tt.cursor)
+			}
+		})
+	}
+}
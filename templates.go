@@ -1,23 +1,28 @@
 package ui
 
-import "html/template"
+import (
+	"html/template"
+	"sync/atomic"
+)
 
-// templates holds the shared template instance set by the renderer
-var templates *template.Template
+// templates holds the shared template instance set by the renderer. It is an atomic
+// pointer because dev mode swaps it from the watcher goroutine while requests read it.
+var templates atomic.Pointer[template.Template]
 
 // SetTemplates sets the shared template instance.
 // Called by HTMLRenderer after parsing to share templates with legacy handlers.
 func SetTemplates(t *template.Template) {
-	templates = t
+	templates.Store(t)
 }
 
 // GetTemplate returns a parsed template by name.
 // Used by legacy domain handlers that haven't been migrated to dependency injection.
 func GetTemplate(name string) *template.Template {
-	if templates == nil {
+	t := templates.Load()
+	if t == nil {
 		return nil
 	}
-	return templates.Lookup(name)
+	return t.Lookup(name)
 }
 
 // NewPageData creates a new PageData with cache version.
@@ -24,6 +24,16 @@ type BulkActionsConfig = types.BulkActionsConfig
 type TableConfig = types.TableConfig
 type ServerPagination = types.ServerPagination
 type PageNumber = types.PageNumber
+type TableQuery = types.TableQuery
+type TableQueryConfig = types.TableQueryConfig
+type FilterCondition = types.FilterCondition
+type Cursor = types.Cursor
+type CursorCodec = types.CursorCodec
+
+// Navigation types
+type Nav = types.Nav
+type NavSection = types.NavSection
+type NavItem = types.NavItem
 
 // Chip types
 type ChipData = types.ChipData
@@ -33,3 +43,8 @@ var ApplyColumnStyles = types.ApplyColumnStyles
 var ApplyTableSettings = types.ApplyTableSettings
 var BuildChipCell = types.BuildChipCell
 var BuildChipCellFromLabels = types.BuildChipCellFromLabels
+var ParseTableQuery = types.ParseTableQuery
+var NewTableQueryConfig = types.NewTableQueryConfig
+var NewCursorCodec = types.NewCursorCodec
+var ParseTableState = types.ParseTableState
+var TableStateParam = types.TableStateParam
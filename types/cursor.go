@@ -0,0 +1,271 @@
+package types
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is wrapped by CursorCodec errors for tokens that are malformed,
+// forged or issued for a different sort. It wraps ErrInvalidTableQuery.
+var ErrInvalidCursor = fmt.Errorf("%w: cursor", ErrInvalidTableQuery)
+
+// minCursorKeyLength is the shortest HMAC key NewCursorCodec accepts
+const minCursorKeyLength = 32
+
+// Cursor is the keyset position of a boundary row: the first row of a page (the
+// prev cursor) or the last (the next cursor)
+type Cursor struct {
+	Sort      string // sort column key the cursor was issued for ("" = unsorted)
+	Direction string // sort direction the cursor was issued for ("asc" or "desc")
+	Values    []any  // the row's sort values, in ORDER BY order (before the tie-breaker)
+	ID        any    // the row's tie-breaker (e.g., primary key)
+}
+
+// IsZero reports whether the cursor has no position (e.g., the boundary row of an empty page)
+func (c Cursor) IsZero() bool {
+	return c.ID == nil && len(c.Values) == 0
+}
+
+// CursorCodec encodes cursors as signed, URL-safe tokens for ServerPagination.NextCursor
+// and PrevCursor. Values keep their Go type through a round trip (string, bool, the int,
+// uint and float kinds, time.Time and nil); the HMAC-SHA256 signature stops clients
+// from forging positions.
+//
+// Example:
+//
+//	cursors, err := types.NewCursorCodec(secret) // once, at startup
+//	...
+//	cur, err := cursors.DecodeQuery(q) // nil on the first page
+//	...
+//	rows := repo.ListPast(cur, q.CursorDirection, q.PageSize+1) // or tablesql's Build
+//	hasMore := len(rows) > q.PageSize
+//	...
+//	sp := q.ServerPagination("/action/client/table")
+//	err = cursors.Paginate(sp, q,
+//	    types.Cursor{Values: []any{first.Name}, ID: first.ID},
+//	    types.Cursor{Values: []any{last.Name}, ID: last.ID},
+//	    hasMore)
+//	sp.BuildDisplay()
+type CursorCodec struct {
+	key []byte
+}
+
+// NewCursorCodec creates a codec signing with key. Use a random secret of at
+// least 32 bytes, shared by every instance serving the table; shorter keys are
+// an error.
+func NewCursorCodec(key []byte) (*CursorCodec, error) {
+	if len(key) < minCursorKeyLength {
+		return nil, fmt.Errorf("failed to create cursor codec: key is %d bytes, use at least %d", len(key), minCursorKeyLength)
+	}
+	return &CursorCodec{key: bytes.Clone(key)}, nil
+}
+
+// cursorPayload is the signed JSON of a cursor token
+type cursorPayload struct {
+	Sort      string        `json:"s,omitempty"`
+	Direction string        `json:"d,omitempty"`
+	Values    []cursorValue `json:"v,omitempty"`
+	ID        cursorValue   `json:"id"`
+}
+
+// cursorValue is a value tagged with its type: [tag, value]
+type cursorValue [2]any
+
+// Encode returns the token for a cursor ("<payload>.<signature>", both base64url)
+func (c *CursorCodec) Encode(cur Cursor) (string, error) {
+	payload := cursorPayload{Sort: cur.Sort, Direction: cur.Direction}
+	for _, v := range cur.Values {
+		tagged, err := tagCursorValue(v)
+		if err != nil {
+			return "", err
+		}
+		payload.Values = append(payload.Values, tagged)
+	}
+	id, err := tagCursorValue(cur.ID)
+	if err != nil {
+		return "", err
+	}
+	payload.ID = id
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(c.sign(data)), nil
+}
+
+// Decode verifies a token and returns its cursor
+func (c *CursorCodec) Decode(token string) (Cursor, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return Cursor{}, fmt.Errorf("%w: malformed token", ErrInvalidCursor)
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: malformed token", ErrInvalidCursor)
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, c.sign(data)) {
+		return Cursor{}, fmt.Errorf("%w: bad signature", ErrInvalidCursor)
+	}
+
+	var payload cursorPayload
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		return Cursor{}, fmt.Errorf("%w: malformed payload", ErrInvalidCursor)
+	}
+	cur := Cursor{Sort: payload.Sort, Direction: payload.Direction}
+	for _, tagged := range payload.Values {
+		v, err := untagCursorValue(tagged)
+		if err != nil {
+			return Cursor{}, err
+		}
+		cur.Values = append(cur.Values, v)
+	}
+	if cur.ID, err = untagCursorValue(payload.ID); err != nil {
+		return Cursor{}, err
+	}
+	return cur, nil
+}
+
+// DecodeQuery decodes the query's cursor, returning nil on the first page (no cursor).
+// A cursor issued for a different sort column or direction is rejected.
+func (c *CursorCodec) DecodeQuery(q TableQuery) (*Cursor, error) {
+	if q.Cursor == "" {
+		return nil, nil
+	}
+	cur, err := c.Decode(q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if cur.Sort != q.SortColumn || cur.Direction != q.SortDirection {
+		return nil, fmt.Errorf("%w: issued for sort %q %s", ErrInvalidCursor, cur.Sort, cur.Direction)
+	}
+	return &cur, nil
+}
+
+// Paginate sets the cursor-mode fields of sp for a page fetched for q. first and last
+// are the page's first and last rows in display order (zero on an empty page); hasMore
+// reports whether the query found rows beyond the page in the direction it read
+// (fetch PageSize+1 rows to tell). Sort and Direction are taken from q.
+func (c *CursorCodec) Paginate(sp *ServerPagination, q TableQuery, first, last Cursor, hasMore bool) error {
+	if q.CursorDirection == "prev" {
+		sp.HasPrevPage = hasMore
+		sp.HasNextPage = true
+	} else {
+		sp.HasPrevPage = q.Cursor != ""
+		sp.HasNextPage = hasMore
+	}
+	sp.PrevCursor, sp.NextCursor = "", ""
+	if first.IsZero() || last.IsZero() {
+		return nil
+	}
+
+	first.Sort, first.Direction = q.SortColumn, q.SortDirection
+	last.Sort, last.Direction = q.SortColumn, q.SortDirection
+	var err error
+	if sp.PrevCursor, err = c.Encode(first); err != nil {
+		return err
+	}
+	if sp.NextCursor, err = c.Encode(last); err != nil {
+		return err
+	}
+	return nil
+}
+
+// sign returns the HMAC-SHA256 of data
+func (c *CursorCodec) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// tagCursorValue tags a value with its type
+func tagCursorValue(v any) (cursorValue, error) {
+	switch v := v.(type) {
+	case nil:
+		return cursorValue{"n", nil}, nil
+	case string:
+		return cursorValue{"s", v}, nil
+	case bool:
+		return cursorValue{"b", v}, nil
+	case int:
+		return cursorValue{"i", int64(v)}, nil
+	case int8:
+		return cursorValue{"i", int64(v)}, nil
+	case int16:
+		return cursorValue{"i", int64(v)}, nil
+	case int32:
+		return cursorValue{"i", int64(v)}, nil
+	case int64:
+		return cursorValue{"i", v}, nil
+	case uint:
+		return cursorValue{"u", uint64(v)}, nil
+	case uint8:
+		return cursorValue{"u", uint64(v)}, nil
+	case uint16:
+		return cursorValue{"u", uint64(v)}, nil
+	case uint32:
+		return cursorValue{"u", uint64(v)}, nil
+	case uint64:
+		return cursorValue{"u", v}, nil
+	case float32:
+		return cursorValue{"f", float64(v)}, nil
+	case float64:
+		return cursorValue{"f", v}, nil
+	case time.Time:
+		return cursorValue{"t", v.Format(time.RFC3339Nano)}, nil
+	}
+	return cursorValue{}, fmt.Errorf("failed to encode cursor: unsupported value type %T", v)
+}
+
+// untagCursorValue restores a tagged value to its Go type
+// (int kinds as int64, uint kinds as uint64, floats as float64)
+func untagCursorValue(tagged cursorValue) (any, error) {
+	tag, _ := tagged[0].(string)
+	switch tag {
+	case "n":
+		return nil, nil
+	case "s":
+		if s, ok := tagged[1].(string); ok {
+			return s, nil
+		}
+	case "b":
+		if b, ok := tagged[1].(bool); ok {
+			return b, nil
+		}
+	case "i":
+		if n, ok := tagged[1].(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				return i, nil
+			}
+		}
+	case "u":
+		if n, ok := tagged[1].(json.Number); ok {
+			if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+				return u, nil
+			}
+		}
+	case "f":
+		if n, ok := tagged[1].(json.Number); ok {
+			if f, err := n.Float64(); err == nil {
+				return f, nil
+			}
+		}
+	case "t":
+		if s, ok := tagged[1].(string); ok {
+			if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: malformed value %v", ErrInvalidCursor, tagged)
+}
@@ -0,0 +1,299 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCursorKey is the HMAC key of the test codecs
+var testCursorKey = []byte("0123456789abcdef0123456789abcdef")
+
+// newTestCodec creates a cursor codec, failing the test on an error
+func newTestCodec(t *testing.T, key []byte) *CursorCodec {
+	t.Helper()
+	codec, err := NewCursorCodec(key)
+	if err != nil {
+		t.Fatalf("NewCursorCodec: %v", err)
+	}
+	return codec
+}
+
+func TestNewCursorCodecKeyLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     []byte
+		wantErr bool
+	}{
+		{"nil", nil, true},
+		{"empty", []byte{}, true},
+		{"one byte short", testCursorKey[:minCursorKeyLength-1], true},
+		{"minimum", testCursorKey[:minCursorKeyLength], false},
+		{"longer", append(bytes.Clone(testCursorKey), "0123"...), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, err := NewCursorCodec(tt.key)
+			if tt.wantErr {
+				if err == nil || codec != nil {
+					t.Errorf("NewCursorCodec = %v, %v, want an error", codec, err)
+				}
+				return
+			}
+			if err != nil || codec == nil {
+				t.Errorf("NewCursorCodec = %v, %v, want a codec", codec, err)
+			}
+		})
+	}
+}
+
+func TestCursorCodecRoundTrip(t *testing.T) {
+	when := time.Date(2024, 3, 9, 14, 30, 0, 123456789, time.UTC)
+
+	tests := []struct {
+		name string
+		cur  Cursor
+		want Cursor // zero = same as cur
+	}{
+		{
+			name: "string and int id",
+			cur:  Cursor{Sort: "name", Direction: "asc", Values: []any{"Bob"}, ID: int64(7)},
+		},
+		{
+			name: "int kinds decode as int64",
+			cur:  Cursor{Sort: "age", Direction: "desc", Values: []any{int32(-42)}, ID: 7},
+			want: Cursor{Sort: "age", Direction: "desc", Values: []any{int64(-42)}, ID: int64(7)},
+		},
+		{
+			name: "uint kinds decode as uint64",
+			cur:  Cursor{Sort: "rank", Direction: "asc", Values: []any{uint8(200)}, ID: uint64(18446744073709551615)},
+			want: Cursor{Sort: "rank", Direction: "asc", Values: []any{uint64(200)}, ID: uint64(18446744073709551615)},
+		},
+		{
+			name: "floats decode as float64",
+			cur:  Cursor{Sort: "amount", Direction: "asc", Values: []any{float32(1.5)}, ID: "a-1"},
+			want: Cursor{Sort: "amount", Direction: "asc", Values: []any{1.5}, ID: "a-1"},
+		},
+		{
+			name: "bool, time and nil",
+			cur:  Cursor{Sort: "created", Direction: "desc", Values: []any{true, when, nil}, ID: int64(1)},
+		},
+		{
+			name: "unsorted",
+			cur:  Cursor{Direction: "asc", ID: int64(3)},
+		},
+	}
+
+	codec := newTestCodec(t, testCursorKey)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := codec.Encode(tt.cur)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			got, err := codec.Decode(token)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			want := tt.want
+			if want.IsZero() {
+				want = tt.cur
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Decode = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestCursorCodecEncodeUnsupported(t *testing.T) {
+	codec := newTestCodec(t, testCursorKey)
+	if _, err := codec.Encode(Cursor{Values: []any{[]string{"a"}}, ID: 1}); err == nil {
+		t.Error("Encode of a slice value succeeded, want an error")
+	}
+}
+
+func TestCursorCodecDecodeInvalid(t *testing.T) {
+	codec := newTestCodec(t, testCursorKey)
+	token, err := codec.Encode(Cursor{Sort: "name", Direction: "asc", Values: []any{"Bob"}, ID: int64(7)})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	payload, signature, _ := strings.Cut(token, ".")
+
+	forged, err := newTestCodec(t, []byte("another-key-another-key-another-k")).Encode(Cursor{Sort: "name", Direction: "asc", Values: []any{"Bob"}, ID: int64(7)})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(`{"s":"name","d":"asc","v":[["s","Zed"]],"id":["i",7]}`))
+
+	// signed tokens whose payload doesn't decode
+	signed := func(data string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(data)) + "." + base64.RawURLEncoding.EncodeToString(codec.sign([]byte(data)))
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no signature", payload},
+		{"truncated signature", payload + "." + signature[:len(signature)-4]},
+		{"truncated payload", payload[:len(payload)-4] + "." + signature},
+		{"payload not base64", "!!!." + signature},
+		{"signature not base64", payload + ".!!!"},
+		{"signed with another key", forged},
+		{"payload changed", tampered + "." + signature},
+		{"signed payload not JSON", signed("not json")},
+		{"signed value with an unknown tag", signed(`{"v":[["x","Bob"]],"id":["i",7]}`)},
+		{"signed value of the wrong type", signed(`{"v":[["i","Bob"]],"id":["i",7]}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := codec.Decode(tt.token)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("Decode error = %v, want ErrInvalidCursor", err)
+			}
+			if !errors.Is(err, ErrInvalidTableQuery) {
+				t.Errorf("Decode error = %v, want it to wrap ErrInvalidTableQuery", err)
+			}
+		})
+	}
+}
+
+func TestCursorCodecDecodeQuery(t *testing.T) {
+	codec := newTestCodec(t, testCursorKey)
+	token, err := codec.Encode(Cursor{Sort: "name", Direction: "asc", Values: []any{"Bob"}, ID: int64(7)})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		q       TableQuery
+		want    *Cursor
+		wantErr bool
+	}{
+		{
+			name: "first page",
+			q:    TableQuery{Mode: "cursor", SortColumn: "name", SortDirection: "asc"},
+		},
+		{
+			name: "same sort",
+			q:    TableQuery{Mode: "cursor", Cursor: token, SortColumn: "name", SortDirection: "asc"},
+			want: &Cursor{Sort: "name", Direction: "asc", Values: []any{"Bob"}, ID: int64(7)},
+		},
+		{
+			name:    "another sort column",
+			q:       TableQuery{Mode: "cursor", Cursor: token, SortColumn: "email", SortDirection: "asc"},
+			wantErr: true,
+		},
+		{
+			name:    "another direction",
+			q:       TableQuery{Mode: "cursor", Cursor: token, SortColumn: "name", SortDirection: "desc"},
+			wantErr: true,
+		},
+		{
+			name:    "unsorted",
+			q:       TableQuery{Mode: "cursor", Cursor: token, SortDirection: "asc"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := codec.DecodeQuery(tt.q)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Errorf("DecodeQuery error = %v, want ErrInvalidCursor", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeQuery: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeQuery = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCursorCodecPaginate(t *testing.T) {
+	first := Cursor{Values: []any{"Ann"}, ID: int64(1)}
+	last := Cursor{Values: []any{"Bob"}, ID: int64(2)}
+
+	tests := []struct {
+		name      string
+		cursor    string
+		curdir    string
+		first     Cursor
+		last      Cursor
+		hasMore   bool
+		wantPrev  bool
+		wantNext  bool
+		noCursors bool
+	}{
+		{name: "first page with more", first: first, last: last, hasMore: true, wantNext: true},
+		{name: "only page", first: first, last: last},
+		{name: "next with more", cursor: "c", curdir: "next", first: first, last: last, hasMore: true, wantPrev: true, wantNext: true},
+		{name: "next at the end", cursor: "c", curdir: "next", first: first, last: last, wantPrev: true},
+		{name: "prev with more", cursor: "c", curdir: "prev", first: first, last: last, hasMore: true, wantPrev: true, wantNext: true},
+		{name: "prev at the start", cursor: "c", curdir: "prev", first: first, last: last, wantNext: true},
+		{name: "empty page", noCursors: true},
+	}
+
+	codec := newTestCodec(t, testCursorKey)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := TableQuery{Mode: "cursor", PageSize: 2, Cursor: tt.cursor, CursorDirection: tt.curdir, SortColumn: "name", SortDirection: "asc"}
+			sp := q.ServerPagination("/table")
+			sp.NextCursor, sp.PrevCursor = "stale", "stale"
+			if err := codec.Paginate(sp, q, tt.first, tt.last, tt.hasMore); err != nil {
+				t.Fatalf("Paginate: %v", err)
+			}
+			if sp.HasPrevPage != tt.wantPrev || sp.HasNextPage != tt.wantNext {
+				t.Errorf("HasPrevPage, HasNextPage = %v, %v, want %v, %v", sp.HasPrevPage, sp.HasNextPage, tt.wantPrev, tt.wantNext)
+			}
+			if tt.noCursors {
+				if sp.PrevCursor != "" || sp.NextCursor != "" {
+					t.Errorf("cursors = %q, %q, want none", sp.PrevCursor, sp.NextCursor)
+				}
+				return
+			}
+
+			for _, c := range []struct {
+				token string
+				want  Cursor
+			}{{sp.PrevCursor, tt.first}, {sp.NextCursor, tt.last}} {
+				got, err := codec.DecodeQuery(TableQuery{Cursor: c.token, SortColumn: "name", SortDirection: "asc"})
+				if err != nil {
+					t.Fatalf("DecodeQuery: %v", err)
+				}
+				want := c.want
+				want.Sort, want.Direction = "name", "asc"
+				if !reflect.DeepEqual(*got, want) {
+					t.Errorf("cursor = %#v, want %#v", *got, want)
+				}
+			}
+		})
+	}
+}
+
+// Tokens must stay URL-safe: they travel unescaped in data attributes and query strings
+func TestCursorCodecTokenIsURLSafe(t *testing.T) {
+	codec := newTestCodec(t, testCursorKey)
+	token, err := codec.Encode(Cursor{Sort: "name", Direction: "asc", Values: []any{"a/b+c?d=e&f"}, ID: "x"})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if strings.ContainsAny(token, "+/=?&") {
+		t.Errorf("token %q is not URL-safe", token)
+	}
+}
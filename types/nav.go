@@ -0,0 +1,114 @@
+package types
+
+// Nav is an app's navigation tree, rendered by the "sidebar" component.
+// Items are matched against PageData.ActiveNav (top-level items) and
+// PageData.ActiveSubNav (sub-items); use ForPage or WithActive to mark them.
+type Nav struct {
+	Label    string       // aria-label of the <nav> element (e.g., "Main navigation")
+	Sections []NavSection // groups of items, rendered in order
+}
+
+// NavSection is a titled group of navigation items
+type NavSection struct {
+	Key         string    // Unique identifier (used for data-section and the persisted collapsed state)
+	Title       string    // Section heading (optional; untitled sections render items only)
+	Items       []NavItem // Items in the section
+	Collapsible bool      // The title toggles the section (requires a Title)
+	Collapsed   bool      // Initially collapsed (WithActive expands a section with an active item)
+}
+
+// NavItem is a link in the navigation tree, optionally with sub-items
+type NavItem struct {
+	Key          string    // Matched against ActiveNav (or ActiveSubNav for sub-items)
+	Label        string    // Display text
+	Href         string    // Link URL (optional for items that only group sub-items)
+	Icon         string    // Icon name (e.g., "users"; see the icon template function)
+	Badge        string    // Badge text (e.g., a count; optional)
+	BadgeVariant string    // Badge variant (e.g., "primary", "error"; default: "neutral")
+	Match        []string  // Additional keys that mark the item active (e.g., detail pages)
+	Items        []NavItem // Sub-items
+	Active       bool      // Set by WithActive: the item or one of its sub-items is current
+	Expanded     bool      // Sub-items are shown (set by WithActive when the item is active)
+}
+
+// ForPage returns a copy of the tree with the page's active items marked
+func (n Nav) ForPage(page PageData) Nav {
+	return n.WithActive(page.ActiveNav, page.ActiveSubNav)
+}
+
+// WithActive returns a copy of the tree with the items matching activeNav and
+// activeSubNav marked Active. A top-level item is active when it matches activeNav;
+// sub-items matching activeSubNav are active only under that item, so sub-items
+// sharing a key under different parents don't both light up. When activeNav is
+// empty, a sub-item matching activeSubNav marks itself and its parent active.
+// Active items with sub-items are Expanded, and sections with an active item are not Collapsed.
+//
+// Example (template):
+//
+//	{{template "sidebar" (.Nav.WithActive .ActiveNav .ActiveSubNav)}}
+func (n Nav) WithActive(activeNav, activeSubNav string) Nav {
+	result := Nav{Label: n.Label, Sections: make([]NavSection, len(n.Sections))}
+	for i, section := range n.Sections {
+		section.Items = markNavItems(section.Items, activeNav, activeSubNav)
+		for _, item := range section.Items {
+			if item.Active {
+				section.Collapsed = false
+			}
+		}
+		result.Sections[i] = section
+	}
+	return result
+}
+
+// ActiveItem returns the active top-level item of a tree marked by WithActive
+func (n Nav) ActiveItem() (NavItem, bool) {
+	for _, section := range n.Sections {
+		for _, item := range section.Items {
+			if item.Active {
+				return item, true
+			}
+		}
+	}
+	return NavItem{}, false
+}
+
+// markNavItems copies items with their Active and Expanded flags set
+func markNavItems(items []NavItem, activeNav, activeSubNav string) []NavItem {
+	if items == nil {
+		return nil
+	}
+	marked := make([]NavItem, len(items))
+	for i, item := range items {
+		item.Active = activeNav != "" && item.matches(activeNav)
+		item.Expanded = false
+		if len(item.Items) > 0 {
+			// With activeNav set, only the matching item's sub-items can be active
+			subNavAllowed := activeNav == "" || item.Active
+			item.Items = make([]NavItem, len(items[i].Items))
+			for j, sub := range items[i].Items {
+				sub.Active = subNavAllowed && activeSubNav != "" && sub.matches(activeSubNav)
+				sub.Expanded = false
+				if sub.Active {
+					item.Active = true
+				}
+				item.Items[j] = sub
+			}
+			item.Expanded = item.Active
+		}
+		marked[i] = item
+	}
+	return marked
+}
+
+// matches reports whether key is the item's key or one of its Match keys
+func (item NavItem) matches(key string) bool {
+	if item.Key == key {
+		return true
+	}
+	for _, match := range item.Match {
+		if match == key {
+			return true
+		}
+	}
+	return false
+}
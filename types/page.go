@@ -4,11 +4,12 @@ import "html/template"
 
 // PageData holds base data passed to all page templates
 type PageData struct {
-	CacheVersion      string
+	CacheVersion      string // Cache-busting version for app-owned assets (package assets use the "asset" template func)
 	Title             string
 	CurrentPath       string
 	ActiveNav         string
 	ActiveSubNav      string // Active sub-navigation item (for sidebar sub-menus)
+	Nav               Nav    // Navigation tree rendered by the "sidebar" component (see Nav.ForPage)
 	HeaderIcon        string
 	HeaderTitle       string
 	HeaderSubtitle    string
@@ -0,0 +1,361 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ErrInvalidTableQuery is wrapped by every ParseTableQuery validation error,
+// so handlers can answer with 400 Bad Request:
+//
+//	if errors.Is(err, types.ErrInvalidTableQuery) { ... }
+var ErrInvalidTableQuery = errors.New("invalid table query")
+
+// Filter operators produced by the filter builder (table-filters.js)
+const (
+	FilterContains   = "contains"
+	FilterEquals     = "equals"
+	FilterStartsWith = "starts_with"
+	FilterEndsWith   = "ends_with"
+	FilterNotEquals  = "not_equals"
+	FilterIsEmpty    = "is_empty"
+	FilterIsNotEmpty = "is_not_empty"
+)
+
+// filterOperators is the set of valid filter operators
+var filterOperators = map[string]bool{
+	FilterContains:   true,
+	FilterEquals:     true,
+	FilterStartsWith: true,
+	FilterEndsWith:   true,
+	FilterNotEquals:  true,
+	FilterIsEmpty:    true,
+	FilterIsNotEmpty: true,
+}
+
+// Defaults applied by ParseTableQuery for zero TableQueryConfig fields
+const (
+	DefaultTablePageSize    = 25
+	DefaultTableMaxPageSize = 100
+	defaultMaxFilters       = 20
+	defaultMaxSearchLength  = 200
+)
+
+// FilterCondition is one condition of the filter builder, as sent in the base64
+// "filters" param. Logic joins the condition to the previous one ("and" or "or");
+// it is ignored on the first condition.
+type FilterCondition struct {
+	Column   string `json:"column"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+	Logic    string `json:"logic"`
+}
+
+// TableQueryConfig configures ParseTableQuery for one table
+type TableQueryConfig struct {
+	Mode                 string   // "offset" (default) or "cursor"
+	DefaultPageSize      int      // page size when the request has none (default: 25)
+	MaxPageSize          int      // larger sizes are clamped to this (default: 100)
+	SortColumns          []string // column keys the table may be sorted by
+	DefaultSortColumn    string   // sort column when the request has none (always allowed)
+	DefaultSortDirection string   // "asc" (default) or "desc"
+	FilterColumns        []string // column keys filter conditions may use (empty = no filters)
+	MaxFilters           int      // maximum filter conditions (default: 20)
+	MaxSearchLength      int      // longer search terms are truncated, in characters (default: 200)
+}
+
+// NewTableQueryConfig creates a query config from a table's columns: sortable
+// columns are allowed sort keys and every column can be filtered. The default
+// sort comes from the table's DefaultSortColumn and DefaultSortDirection.
+func NewTableQueryConfig(table TableConfig) TableQueryConfig {
+	cfg := TableQueryConfig{
+		DefaultSortColumn:    table.DefaultSortColumn,
+		DefaultSortDirection: table.DefaultSortDirection,
+	}
+	if table.ServerPagination != nil {
+		cfg.Mode = table.ServerPagination.Mode
+	}
+
+	columns := table.Columns
+	for _, group := range table.ColumnGroups {
+		columns = append(columns[:len(columns):len(columns)], group.Columns...)
+	}
+	for _, col := range columns {
+		if col.Key == "" {
+			continue
+		}
+		if col.Sortable {
+			cfg.SortColumns = append(cfg.SortColumns, col.Key)
+		}
+		cfg.FilterColumns = append(cfg.FilterColumns, col.Key)
+	}
+	return cfg
+}
+
+// TableQuery is a validated server-pagination request (see ParseTableQuery)
+type TableQuery struct {
+	Mode            string            // "offset" or "cursor"
+	Page            int               // 1-based page number (offset mode)
+	PageSize        int               // rows per page
+	Cursor          string            // opaque cursor token (cursor mode; "" = first page)
+	CursorDirection string            // "next" or "prev" ("" when there is no cursor)
+	Search          string            // trimmed search term
+	SortColumn      string            // allowed sort column key ("" = unsorted)
+	SortDirection   string            // "asc" or "desc"
+	Filters         []FilterCondition // decoded filter conditions
+	FiltersParam    string            // Filters re-encoded as the "filters" param ("" when none)
+}
+
+// ParseTableQuery reads and validates the params table-server.js sends: page, size,
+// cursor, curdir, search, sort, dir and the base64 JSON filters. Sizes above
+// cfg.MaxPageSize are clamped; anything else invalid (a sort or filter column
+// not allowed by cfg, an unknown operator, malformed filters) returns an error
+// wrapping ErrInvalidTableQuery.
+//
+// Example:
+//
+//	q, err := types.ParseTableQuery(r, types.NewTableQueryConfig(table))
+//	if err != nil {
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	    return
+//	}
+//	total := repo.Count(q.Search, q.Filters)
+//	q.ClampPage(total)
+//	rows := repo.List(q.Offset(), q.PageSize, q.SortColumn, q.SortDirection, q.Filters)
+//	table.ServerPagination = q.ServerPagination("/action/client/table")
+//	table.ServerPagination.SetTotalRows(total)
+//	table.ServerPagination.BuildDisplay()
+func ParseTableQuery(r *http.Request, cfg TableQueryConfig) (TableQuery, error) {
+	return parseTableQuery(r.URL.Query(), cfg)
+}
+
+// parseTableQuery reads and validates the table params (see ParseTableQuery)
+func parseTableQuery(params url.Values, cfg TableQueryConfig) (TableQuery, error) {
+	q := TableQuery{
+		Mode:     cfg.Mode,
+		Page:     1,
+		PageSize: cfg.DefaultPageSize,
+	}
+	if q.Mode == "" {
+		q.Mode = "offset"
+	}
+	if q.Mode != "offset" && q.Mode != "cursor" {
+		return TableQuery{}, fmt.Errorf("%w: unknown pagination mode %q", ErrInvalidTableQuery, q.Mode)
+	}
+	maxSize := cfg.MaxPageSize
+	if maxSize <= 0 {
+		maxSize = DefaultTableMaxPageSize
+	}
+	if q.PageSize <= 0 {
+		q.PageSize = DefaultTablePageSize
+	}
+	q.PageSize = min(q.PageSize, maxSize)
+
+	// Page and size
+	if v := params.Get("page"); v != "" && q.Mode == "offset" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return TableQuery{}, fmt.Errorf("%w: page %q", ErrInvalidTableQuery, v)
+		}
+		q.Page = page
+	}
+	if v := params.Get("size"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil || size < 1 {
+			return TableQuery{}, fmt.Errorf("%w: size %q", ErrInvalidTableQuery, v)
+		}
+		q.PageSize = min(size, maxSize)
+	}
+
+	// Cursor
+	if q.Mode == "cursor" {
+		q.Cursor = params.Get("cursor")
+		q.CursorDirection = params.Get("curdir")
+		switch {
+		case q.Cursor == "":
+			q.CursorDirection = ""
+		case q.CursorDirection == "":
+			q.CursorDirection = "next"
+		case q.CursorDirection != "next" && q.CursorDirection != "prev":
+			return TableQuery{}, fmt.Errorf("%w: curdir %q", ErrInvalidTableQuery, q.CursorDirection)
+		}
+	}
+
+	// Search
+	q.Search = strings.TrimSpace(params.Get("search"))
+	maxSearch := cfg.MaxSearchLength
+	if maxSearch <= 0 {
+		maxSearch = defaultMaxSearchLength
+	}
+	if utf8.RuneCountInString(q.Search) > maxSearch {
+		q.Search = string([]rune(q.Search)[:maxSearch])
+	}
+
+	// Sort
+	q.SortColumn = cfg.DefaultSortColumn
+	q.SortDirection = cfg.DefaultSortDirection
+	if v := params.Get("sort"); v != "" {
+		if v != cfg.DefaultSortColumn && !slices.Contains(cfg.SortColumns, v) {
+			return TableQuery{}, fmt.Errorf("%w: sort column %q is not allowed", ErrInvalidTableQuery, v)
+		}
+		q.SortColumn = v
+	}
+	if v := params.Get("dir"); v != "" {
+		q.SortDirection = strings.ToLower(v)
+	}
+	if q.SortDirection == "" {
+		q.SortDirection = "asc"
+	}
+	if q.SortDirection != "asc" && q.SortDirection != "desc" {
+		return TableQuery{}, fmt.Errorf("%w: sort direction %q", ErrInvalidTableQuery, q.SortDirection)
+	}
+
+	// Filters
+	if v := params.Get("filters"); v != "" {
+		filters, err := DecodeFilters(v)
+		if err != nil {
+			return TableQuery{}, err
+		}
+		maxFilters := cfg.MaxFilters
+		if maxFilters <= 0 {
+			maxFilters = defaultMaxFilters
+		}
+		if len(filters) > maxFilters {
+			return TableQuery{}, fmt.Errorf("%w: %d filter conditions (maximum %d)", ErrInvalidTableQuery, len(filters), maxFilters)
+		}
+		for _, f := range filters {
+			if !slices.Contains(cfg.FilterColumns, f.Column) {
+				return TableQuery{}, fmt.Errorf("%w: filter column %q is not allowed", ErrInvalidTableQuery, f.Column)
+			}
+		}
+		q.Filters = filters
+		q.FiltersParam = EncodeFilters(filters)
+	}
+
+	return q, nil
+}
+
+// Offset returns the number of rows before the current page (offset mode)
+func (q TableQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
+}
+
+// ClampPage moves Page back to the last page when it is past the end of total rows
+// (e.g., a link to page 9 after rows were deleted). Call it before Offset, so the rows
+// fetched are the ones SetTotalRows will report as the current page.
+func (q *TableQuery) ClampPage(total int) {
+	if q.PageSize <= 0 {
+		return
+	}
+	if last := (total + q.PageSize - 1) / q.PageSize; q.Page > last {
+		q.Page = last
+	}
+	if q.Page < 1 {
+		q.Page = 1
+	}
+}
+
+// ServerPagination returns the pagination state for the query, with the current page,
+// size, search, sort and filters set. Set the totals (SetTotalRows, or HasNextPage and
+// the cursors in cursor mode), then call BuildDisplay.
+func (q TableQuery) ServerPagination(paginationURL string) *ServerPagination {
+	return &ServerPagination{
+		Enabled:       true,
+		Mode:          q.Mode,
+		PageSize:      q.PageSize,
+		CurrentPage:   q.Page,
+		SearchQuery:   q.Search,
+		SortColumn:    q.SortColumn,
+		SortDirection: q.SortDirection,
+		FiltersJSON:   q.FiltersParam,
+		PaginationURL: paginationURL,
+	}
+}
+
+// DecodeFilters decodes the base64 JSON "filters" param, validating operators and logic.
+// Both standard and URL-safe base64 are accepted, with or without padding, and spaces
+// (a '+' from an unescaped query string) are read as '+'.
+func DecodeFilters(param string) ([]FilterCondition, error) {
+	param = strings.ReplaceAll(param, " ", "+")
+	var data []byte
+	var err error
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if data, err = enc.DecodeString(param); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: filters are not base64", ErrInvalidTableQuery)
+	}
+
+	// btoa() encodes Latin-1; read such bytes as their code points
+	if !utf8.Valid(data) {
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		data = []byte(string(runes))
+	}
+
+	var filters []FilterCondition
+	if err := json.Unmarshal(data, &filters); err != nil {
+		return nil, fmt.Errorf("%w: filters are not a JSON condition list: %v", ErrInvalidTableQuery, err)
+	}
+
+	for i := range filters {
+		f := &filters[i]
+		if f.Column == "" {
+			return nil, fmt.Errorf("%w: filter %d has no column", ErrInvalidTableQuery, i+1)
+		}
+		if !filterOperators[f.Operator] {
+			return nil, fmt.Errorf("%w: filter operator %q", ErrInvalidTableQuery, f.Operator)
+		}
+		f.Logic = strings.ToLower(f.Logic)
+		if f.Logic == "" || i == 0 {
+			f.Logic = "and"
+		}
+		if f.Logic != "and" && f.Logic != "or" {
+			return nil, fmt.Errorf("%w: filter logic %q", ErrInvalidTableQuery, f.Logic)
+		}
+		if f.Operator == FilterIsEmpty || f.Operator == FilterIsNotEmpty {
+			f.Value = ""
+		}
+	}
+	return filters, nil
+}
+
+// EncodeFilters encodes filter conditions as the base64 JSON "filters" param.
+// Non-ASCII characters are \u-escaped, as TableServer.encodeFilters does, so the
+// browser's atob() and JSON.parse() read them back unchanged.
+func EncodeFilters(filters []FilterCondition) string {
+	if len(filters) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return ""
+	}
+
+	var ascii strings.Builder
+	for _, r := range string(data) {
+		switch {
+		case r < utf8.RuneSelf:
+			ascii.WriteRune(r)
+		case r > 0xFFFF:
+			hi, lo := utf16.EncodeRune(r)
+			fmt.Fprintf(&ascii, `\u%04x\u%04x`, hi, lo)
+		default:
+			fmt.Fprintf(&ascii, `\u%04x`, r)
+		}
+	}
+	return base64.StdEncoding.EncodeToString([]byte(ascii.String()))
+}
@@ -0,0 +1,324 @@
+package types
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testQueryConfig is the config of the ParseTableQuery tests
+var testQueryConfig = TableQueryConfig{
+	DefaultPageSize:      10,
+	MaxPageSize:          50,
+	SortColumns:          []string{"name", "email"},
+	DefaultSortColumn:    "created",
+	DefaultSortDirection: "desc",
+	FilterColumns:        []string{"name", "status"},
+	MaxFilters:           2,
+}
+
+// encodeTestFilters base64-encodes a filters JSON payload as btoa() would
+func encodeTestFilters(payload string) string {
+	return base64.StdEncoding.EncodeToString([]byte(payload))
+}
+
+func TestParseTableQuery(t *testing.T) {
+	cursorConfig := testQueryConfig
+	cursorConfig.Mode = "cursor"
+	shortSearch := testQueryConfig
+	shortSearch.MaxSearchLength = 3
+	largeDefault := testQueryConfig
+	largeDefault.DefaultPageSize = 80
+
+	filters := []FilterCondition{
+		{Column: "name", Operator: FilterContains, Value: "ann", Logic: "and"},
+		{Column: "status", Operator: FilterEquals, Value: "active", Logic: "or"},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		cfg   *TableQueryConfig // nil = testQueryConfig
+		want  TableQuery
+	}{
+		{
+			name: "defaults",
+			want: TableQuery{Mode: "offset", Page: 1, PageSize: 10, SortColumn: "created", SortDirection: "desc"},
+		},
+		{
+			name: "zero config defaults",
+			cfg:  &TableQueryConfig{},
+			want: TableQuery{Mode: "offset", Page: 1, PageSize: DefaultTablePageSize, SortDirection: "asc"},
+		},
+		{
+			name:  "page and size",
+			query: "page=3&size=20",
+			want:  TableQuery{Mode: "offset", Page: 3, PageSize: 20, SortColumn: "created", SortDirection: "desc"},
+		},
+		{
+			name:  "size clamped to the maximum",
+			query: "size=500",
+			want:  TableQuery{Mode: "offset", Page: 1, PageSize: 50, SortColumn: "created", SortDirection: "desc"},
+		},
+		{
+			name:  "size clamped to the default maximum",
+			query: "size=500",
+			cfg:   &TableQueryConfig{},
+			want:  TableQuery{Mode: "offset", Page: 1, PageSize: DefaultTableMaxPageSize, SortDirection: "asc"},
+		},
+		{
+			name: "default size clamped to the maximum",
+			cfg:  &largeDefault,
+			want: TableQuery{Mode: "offset", Page: 1, PageSize: 50, SortColumn: "created", SortDirection: "desc"},
+		},
+		{
+			name:  "allowed sort column, direction case-insensitive",
+			query: "sort=name&dir=ASC",
+			want:  TableQuery{Mode: "offset", Page: 1, PageSize: 10, SortColumn: "name", SortDirection: "asc"},
+		},
+		{
+			name:  "default sort column is always allowed",
+			query: "sort=created",
+			want:  TableQuery{Mode: "offset", Page: 1, PageSize: 10, SortColumn: "created", SortDirection: "desc"},
+		},
+		{
+			name:  "search trimmed and truncated by character",
+			query: "search=%20h%C3%A9llo%20",
+			cfg:   &shortSearch,
+			want:  TableQuery{Mode: "offset", Page: 1, PageSize: 10, Search: "hél", SortColumn: "created", SortDirection: "desc"},
+		},
+		{
+			name:  "offset mode ignores cursors",
+			query: "cursor=abc&curdir=prev",
+			want:  TableQuery{Mode: "offset", Page: 1, PageSize: 10, SortColumn: "created", SortDirection: "desc"},
+		},
+		{
+			name:  "cursor defaults to next, page ignored",
+			query: "cursor=abc&page=5",
+			cfg:   &cursorConfig,
+			want:  TableQuery{Mode: "cursor", Page: 1, PageSize: 10, Cursor: "abc", CursorDirection: "next", SortColumn: "created", SortDirection: "desc"},
+		},
+		{
+			name:  "cursor prev",
+			query: "cursor=abc&curdir=prev",
+			cfg:   &cursorConfig,
+			want:  TableQuery{Mode: "cursor", Page: 1, PageSize: 10, Cursor: "abc", CursorDirection: "prev", SortColumn: "created", SortDirection: "desc"},
+		},
+		{
+			name:  "curdir without a cursor is dropped",
+			query: "curdir=prev",
+			cfg:   &cursorConfig,
+			want:  TableQuery{Mode: "cursor", Page: 1, PageSize: 10, SortColumn: "created", SortDirection: "desc"},
+		},
+		{
+			name:  "filters",
+			query: "filters=" + url.QueryEscape(encodeTestFilters(`[{"column":"name","operator":"contains","value":"ann"},{"column":"status","operator":"equals","value":"active","logic":"OR"}]`)),
+			want: TableQuery{Mode: "offset", Page: 1, PageSize: 10, SortColumn: "created", SortDirection: "desc",
+				Filters: filters, FiltersParam: EncodeFilters(filters)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testQueryConfig
+			if tt.cfg != nil {
+				cfg = *tt.cfg
+			}
+			params, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := parseTableQuery(params, cfg)
+			if err != nil {
+				t.Fatalf("parseTableQuery: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("query =\n %+v\nwant\n %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTableQueryErrors(t *testing.T) {
+	cursorConfig := testQueryConfig
+	cursorConfig.Mode = "cursor"
+	filter := func(column string) string {
+		return `{"column":"` + column + `","operator":"contains","value":"a"}`
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		cfg   *TableQueryConfig // nil = testQueryConfig
+	}{
+		{name: "unknown mode", cfg: &TableQueryConfig{Mode: "infinite"}},
+		{name: "page zero", query: "page=0"},
+		{name: "page not a number", query: "page=two"},
+		{name: "size zero", query: "size=0"},
+		{name: "negative size", query: "size=-5"},
+		{name: "sort column not allowed", query: "sort=password"},
+		{name: "invalid dir", query: "dir=up"},
+		{name: "invalid curdir", query: "cursor=abc&curdir=sideways", cfg: &cursorConfig},
+		{name: "filters not base64", query: "filters=%21%21%21"},
+		{name: "filter column not allowed", query: "filters=" + url.QueryEscape(encodeTestFilters("["+filter("email")+"]"))},
+		{name: "too many filters", query: "filters=" + url.QueryEscape(encodeTestFilters("["+filter("name")+","+filter("name")+","+filter("status")+"]"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testQueryConfig
+			if tt.cfg != nil {
+				cfg = *tt.cfg
+			}
+			params, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := parseTableQuery(params, cfg); !errors.Is(err, ErrInvalidTableQuery) {
+				t.Errorf("parseTableQuery error = %v, want ErrInvalidTableQuery", err)
+			}
+		})
+	}
+}
+
+func TestDecodeFilters(t *testing.T) {
+	// "?>>" makes the standard encoding contain '+' and the URL-safe one '-'
+	payload := `[{"column":"name","operator":"contains","value":"?>>"}]`
+	std := base64.StdEncoding.EncodeToString([]byte(payload))
+	if !strings.Contains(std, "+") || !strings.HasSuffix(std, "=") {
+		t.Fatalf("test payload %s should need '+' and padding", std)
+	}
+	want := []FilterCondition{{Column: "name", Operator: FilterContains, Value: "?>>", Logic: "and"}}
+
+	tests := []struct {
+		name  string
+		param string
+		want  []FilterCondition
+	}{
+		{"standard", std, want},
+		{"standard without padding", base64.RawStdEncoding.EncodeToString([]byte(payload)), want},
+		{"url-safe", base64.URLEncoding.EncodeToString([]byte(payload)), want},
+		{"url-safe without padding", base64.RawURLEncoding.EncodeToString([]byte(payload)), want},
+		{"'+' read back from an unescaped query string", strings.ReplaceAll(std, "+", " "), want},
+		{
+			name:  "latin-1 payload (btoa)",
+			param: encodeTestFilters("[{\"column\":\"name\",\"operator\":\"equals\",\"value\":\"caf\xe9\"}]"),
+			want:  []FilterCondition{{Column: "name", Operator: FilterEquals, Value: "café", Logic: "and"}},
+		},
+		{
+			name:  "utf-8 payload",
+			param: encodeTestFilters(`[{"column":"name","operator":"equals","value":"café 日本"}]`),
+			want:  []FilterCondition{{Column: "name", Operator: FilterEquals, Value: "café 日本", Logic: "and"}},
+		},
+		{
+			name:  "logic normalised, first condition's ignored",
+			param: encodeTestFilters(`[{"column":"a","operator":"equals","value":"1","logic":"or"},{"column":"b","operator":"equals","value":"2","logic":"OR"},{"column":"c","operator":"equals","value":"3"}]`),
+			want: []FilterCondition{
+				{Column: "a", Operator: FilterEquals, Value: "1", Logic: "and"},
+				{Column: "b", Operator: FilterEquals, Value: "2", Logic: "or"},
+				{Column: "c", Operator: FilterEquals, Value: "3", Logic: "and"},
+			},
+		},
+		{
+			name:  "empty operators drop the value",
+			param: encodeTestFilters(`[{"column":"a","operator":"is_empty","value":"x"}]`),
+			want:  []FilterCondition{{Column: "a", Operator: FilterIsEmpty, Logic: "and"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeFilters(tt.param)
+			if err != nil {
+				t.Fatalf("DecodeFilters: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filters = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeFiltersErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		param string
+	}{
+		{"not base64", "!!!"},
+		{"not JSON", encodeTestFilters("not json")},
+		{"not a list", encodeTestFilters(`{"column":"a","operator":"equals"}`)},
+		{"no column", encodeTestFilters(`[{"operator":"equals","value":"x"}]`)},
+		{"unknown operator", encodeTestFilters(`[{"column":"a","operator":"matches","value":"x"}]`)},
+		{"unknown logic", encodeTestFilters(`[{"column":"a","operator":"equals"},{"column":"b","operator":"equals","logic":"xor"}]`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeFilters(tt.param); !errors.Is(err, ErrInvalidTableQuery) {
+				t.Errorf("DecodeFilters error = %v, want ErrInvalidTableQuery", err)
+			}
+		})
+	}
+}
+
+func TestEncodeFiltersRoundTrip(t *testing.T) {
+	filters := []FilterCondition{
+		{Column: "name", Operator: FilterContains, Value: "plain", Logic: "and"},
+		{Column: "name", Operator: FilterEquals, Value: "café", Logic: "or"},
+		{Column: "name", Operator: FilterStartsWith, Value: "日本", Logic: "and"},
+		{Column: "name", Operator: FilterEndsWith, Value: "party 🎉 𝄞", Logic: "or"},
+	}
+
+	encoded := EncodeFilters(filters)
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("EncodeFilters is not standard base64: %v", err)
+	}
+	for _, b := range data {
+		if b >= 0x80 {
+			t.Fatalf("EncodeFilters JSON %s is not ASCII (atob() would garble it)", data)
+		}
+	}
+
+	got, err := DecodeFilters(encoded)
+	if err != nil {
+		t.Fatalf("DecodeFilters: %v", err)
+	}
+	if !reflect.DeepEqual(got, filters) {
+		t.Errorf("round trip = %+v, want %+v", got, filters)
+	}
+
+	if EncodeFilters(nil) != "" {
+		t.Errorf("EncodeFilters(nil) = %q, want empty", EncodeFilters(nil))
+	}
+}
+
+func TestClampPage(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     int
+		pageSize int
+		total    int
+		want     int
+	}{
+		{"within range", 2, 10, 35, 2},
+		{"last page", 4, 10, 35, 4},
+		{"past the end", 9, 10, 35, 4},
+		{"exact multiple", 5, 10, 40, 4},
+		{"no rows", 3, 10, 0, 1},
+		{"no page size", 3, 0, 35, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := TableQuery{Page: tt.page, PageSize: tt.pageSize}
+			q.ClampPage(tt.total)
+			if q.Page != tt.want {
+				t.Errorf("Page = %d, want %d", q.Page, tt.want)
+			}
+		})
+	}
+}
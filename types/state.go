@@ -0,0 +1,75 @@
+package types
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// TableStateParam returns the page-URL param a table's state param is kept under
+// when TableConfig.URLState is set (e.g., "clients.page" for table "clients")
+func TableStateParam(tableID, param string) string {
+	return tableID + "." + param
+}
+
+// ParseTableState restores a table's state from the page URL on a full-page render
+// of a table with URLState set: the "<tableID>.page", "<tableID>.search", ... params
+// table-server.js keeps in the address bar, validated like ParseTableQuery. Other
+// params, including other tables' state, are ignored. Invalid state (e.g., a link
+// saved before a column was removed) returns the default query along with the error,
+// so the page still renders. An invalid cfg (e.g., an unknown Mode) has no default
+// query: the zero TableQuery is returned with the error.
+//
+// Example:
+//
+//	q, err := types.ParseTableState(r, table.ID, types.NewTableQueryConfig(table))
+//	if err != nil {
+//	    log.Printf("Warning: Ignoring table state: %v", err)
+//	}
+//	total := repo.Count(q.Search, q.Filters)
+//	q.ClampPage(total)
+//	rows := repo.List(q.Offset(), q.PageSize, q.SortColumn, q.SortDirection, q.Filters)
+//	table.ServerPagination = q.ServerPagination("/action/client/table")
+//	table.ServerPagination.SetTotalRows(total)
+//	table.ServerPagination.BuildDisplay()
+func ParseTableState(r *http.Request, tableID string, cfg TableQueryConfig) (TableQuery, error) {
+	prefix := TableStateParam(tableID, "")
+	params := url.Values{}
+	for key, values := range r.URL.Query() {
+		if name, ok := strings.CutPrefix(key, prefix); ok && name != "" {
+			params[name] = values
+		}
+	}
+	q, err := parseTableQuery(params, cfg)
+	if err != nil {
+		q, _ = parseTableQuery(url.Values{}, cfg)
+		return q, err
+	}
+	return q, nil
+}
+
+// StateValues returns the query's state as the page-URL params ParseTableState reads
+// (e.g., for a link or redirect back to the page). The first page and empty values are omitted.
+func (q TableQuery) StateValues(tableID string) url.Values {
+	params := url.Values{}
+	set := func(name, value string) {
+		if value != "" {
+			params.Set(TableStateParam(tableID, name), value)
+		}
+	}
+	if q.Mode != "cursor" && q.Page > 1 {
+		set("page", itoa(q.Page))
+	}
+	if q.PageSize > 0 {
+		set("size", itoa(q.PageSize))
+	}
+	set("cursor", q.Cursor)
+	set("curdir", q.CursorDirection)
+	set("search", q.Search)
+	if q.SortColumn != "" {
+		set("sort", q.SortColumn)
+		set("dir", q.SortDirection)
+	}
+	set("filters", q.FiltersParam)
+	return params
+}
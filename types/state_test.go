@@ -0,0 +1,112 @@
+package types
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestTableStateRoundTrip(t *testing.T) {
+	cursorConfig := testQueryConfig
+	cursorConfig.Mode = "cursor"
+	filters := []FilterCondition{{Column: "name", Operator: FilterContains, Value: "日本 🎉", Logic: "and"}}
+
+	tests := []struct {
+		name string
+		cfg  TableQueryConfig
+		q    TableQuery
+	}{
+		{
+			name: "defaults",
+			cfg:  testQueryConfig,
+			q:    TableQuery{Mode: "offset", Page: 1, PageSize: 10, SortColumn: "created", SortDirection: "desc"},
+		},
+		{
+			name: "offset state",
+			cfg:  testQueryConfig,
+			q: TableQuery{Mode: "offset", Page: 3, PageSize: 20, Search: "a&b #1", SortColumn: "name", SortDirection: "asc",
+				Filters: filters, FiltersParam: EncodeFilters(filters)},
+		},
+		{
+			name: "cursor state",
+			cfg:  cursorConfig,
+			q:    TableQuery{Mode: "cursor", Page: 1, PageSize: 10, Cursor: "ab+c/d==", CursorDirection: "prev", SortColumn: "email", SortDirection: "desc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/clients?"+tt.q.StateValues("clients").Encode(), nil)
+			got, err := ParseTableState(r, "clients", tt.cfg)
+			if err != nil {
+				t.Fatalf("ParseTableState: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.q) {
+				t.Errorf("state =\n %+v\nwant\n %+v", got, tt.q)
+			}
+		})
+	}
+}
+
+func TestTableStateValues(t *testing.T) {
+	q := TableQuery{Mode: "offset", Page: 1, PageSize: 10, Search: "x", SortDirection: "desc"}
+	// the first page, an empty cursor and a direction without a sort column are omitted
+	if got, want := q.StateValues("clients").Encode(), "clients.search=x&clients.size=10"; got != want {
+		t.Errorf("StateValues = %q, want %q", got, want)
+	}
+}
+
+func TestParseTableStateIsolatesTables(t *testing.T) {
+	r := httptest.NewRequest("GET", "/clients?page=7&tab=open"+
+		"&clients.page=2&clients.search=ann&clients.sort=name"+
+		"&clients2.page=5&clients2.search=bob&clients2.dir=asc", nil)
+
+	tests := []struct {
+		tableID string
+		want    TableQuery
+	}{
+		{"clients", TableQuery{Mode: "offset", Page: 2, PageSize: 10, Search: "ann", SortColumn: "name", SortDirection: "desc"}},
+		{"clients2", TableQuery{Mode: "offset", Page: 5, PageSize: 10, Search: "bob", SortColumn: "created", SortDirection: "asc"}},
+		{"invoices", TableQuery{Mode: "offset", Page: 1, PageSize: 10, SortColumn: "created", SortDirection: "desc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tableID, func(t *testing.T) {
+			got, err := ParseTableState(r, tt.tableID, testQueryConfig)
+			if err != nil {
+				t.Fatalf("ParseTableState: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("state =\n %+v\nwant\n %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTableStateInvalid(t *testing.T) {
+	t.Run("invalid state returns the default query", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/clients?clients.page=4&clients.sort=password", nil)
+		got, err := ParseTableState(r, "clients", testQueryConfig)
+		if !errors.Is(err, ErrInvalidTableQuery) {
+			t.Errorf("ParseTableState error = %v, want ErrInvalidTableQuery", err)
+		}
+		want := TableQuery{Mode: "offset", Page: 1, PageSize: 10, SortColumn: "created", SortDirection: "desc"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("state = %+v, want the default %+v", got, want)
+		}
+	})
+
+	t.Run("invalid config returns the zero query", func(t *testing.T) {
+		cfg := testQueryConfig
+		cfg.Mode = "infinite"
+		r := httptest.NewRequest("GET", "/clients?clients.page=2", nil)
+		got, err := ParseTableState(r, "clients", cfg)
+		if !errors.Is(err, ErrInvalidTableQuery) {
+			t.Errorf("ParseTableState error = %v, want ErrInvalidTableQuery", err)
+		}
+		if !reflect.DeepEqual(got, TableQuery{}) {
+			t.Errorf("state = %+v, want the zero TableQuery", got)
+		}
+	})
+}
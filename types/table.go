@@ -2,6 +2,7 @@ package types
 
 import (
 	"html/template"
+	"net/url"
 	"strconv"
 )
 
@@ -185,7 +186,7 @@ type BulkAction struct {
 	// Unified bulk action configuration (for bulk-action.js):
 	Endpoint        string // POST endpoint URL (e.g., "/action/regulations/pay-items/bulk-delete")
 	ConfirmTitle    string // Dialog title (e.g., "Delete Pay Items")
-	ConfirmMessage  string // Message with {{count}} placeholder (e.g., "Delete {{count}} pay item(s)?")
+	ConfirmMessage  string // Message formatted with count (e.g., "Delete {count, plural, one {# pay item} other {# pay items}}?"; {{count}} also works)
 	ExtraParamsJSON string // Pre-rendered JSON for extra form params (e.g., '{"bulk_action":"set-admin-manager"}')
 	// Dynamic visibility based on selected rows:
 	RequiresDataAttr string // Data attribute name that must be "true" on ALL selected rows (e.g., "deletable")
@@ -196,7 +197,7 @@ type BulkActionsConfig struct {
 	Enabled        bool         // Enable bulk selection mode
 	Actions        []BulkAction // Available bulk actions
 	SelectAllLabel string       // Label for "Select all" text
-	SelectedLabel  string       // Label template for selected count (e.g., "{count} selected")
+	SelectedLabel  string       // Label message for the selected count (e.g., "{count, plural, one {# row} other {# rows}} selected")
 	CancelLabel    string       // Label for cancel/clear selection button
 }
 
@@ -229,6 +230,7 @@ type TableConfig struct {
 	BulkActions          *BulkActionsConfig // Optional bulk selection configuration
 	FixedLayout          bool               // When true, use table-layout: fixed (columns respect declared widths exactly)
 	ServerPagination     *ServerPagination  // Optional server-side pagination configuration (nil = client-side mode)
+	URLState             bool               // Keep server-pagination state in the page URL, namespaced by ID (see ParseTableState)
 }
 
 // ImportAction defines the import button configuration
@@ -252,14 +254,15 @@ type ServerPagination struct {
 	EndRow        int    // pre-calculated last row number on current page (for display)
 	HasNextPage   bool   // more rows forward?
 	HasPrevPage   bool   // more rows backward?
-	NextCursor    string // cursor mode: cursor token for next page (base64 encoded)
-	PrevCursor    string // cursor mode: cursor token for previous page (base64 encoded)
+	NextCursor    string // cursor mode: cursor token for next page (base64 encoded, see CursorCodec)
+	PrevCursor    string // cursor mode: cursor token for previous page (base64 encoded, see CursorCodec)
 	SearchQuery   string // current search term (reflected in search input)
 	SortColumn    string // current sort column key
 	SortDirection string // current sort direction ("asc" or "desc")
 	FiltersJSON   string // current advanced filters (base64 encoded JSON)
-	PaginationURL     string // base URL for HTMX page requests
-	PaginationBodyURL string // base URL for body-only targeted swap requests
+	PaginationURL     string     // base URL for HTMX page requests (its own query params are kept)
+	PaginationBodyURL string     // base URL for body-only targeted swap requests
+	Params            url.Values // extra params kept on every pagination URL (e.g., tab, status)
 
 	// Pre-computed display fields — populated by BuildDisplay()
 	PageNumbers   []PageNumber // page buttons for offset mode (with smart windowing)
@@ -267,6 +270,12 @@ type ServerPagination struct {
 	NextPageURL   string       // HTMX URL for next page button (offset mode)
 	PrevCursorURL string       // HTMX URL for prev button (cursor mode)
 	NextCursorURL string       // HTMX URL for next button (cursor mode)
+
+	// Body-only counterparts, populated by BuildDisplay() when PaginationBodyURL is set
+	PrevPageBodyURL   string
+	NextPageBodyURL   string
+	PrevCursorBodyURL string
+	NextCursorBodyURL string
 }
 
 // PageNumber represents a single page button in the pagination UI
@@ -275,6 +284,7 @@ type PageNumber struct {
 	Active   bool   // true if this is the current page
 	Ellipsis bool   // true if this is a "..." separator
 	URL      string // pre-built HTMX URL for this page
+	BodyURL  string // pre-built body-only URL for this page ("" without PaginationBodyURL)
 }
 
 // BuildDisplay pre-computes all display fields (StartRow, EndRow, PageNumbers, URLs).
@@ -287,6 +297,23 @@ func (sp *ServerPagination) BuildDisplay() {
 	}
 }
 
+// SetTotalRows sets TotalRows and TotalPages (offset mode), moving CurrentPage
+// back to the last page when it is past the end. It doesn't re-fetch rows: call
+// TableQuery.ClampPage with the same total before querying with Offset.
+func (sp *ServerPagination) SetTotalRows(total int) {
+	sp.TotalRows = total
+	sp.TotalPages = 0
+	if sp.PageSize > 0 {
+		sp.TotalPages = (total + sp.PageSize - 1) / sp.PageSize
+	}
+	if sp.CurrentPage > sp.TotalPages && sp.TotalPages > 0 {
+		sp.CurrentPage = sp.TotalPages
+	}
+	if sp.CurrentPage < 1 {
+		sp.CurrentPage = 1
+	}
+}
+
 // buildOffsetDisplay computes offset-mode display fields
 func (sp *ServerPagination) buildOffsetDisplay() {
 	// StartRow and EndRow
@@ -306,64 +333,126 @@ func (sp *ServerPagination) buildOffsetDisplay() {
 
 	// Prev/Next page URLs
 	if sp.HasPrevPage {
-		sp.PrevPageURL = sp.buildPageURL(sp.CurrentPage - 1)
+		sp.PrevPageURL = sp.PageURL(sp.CurrentPage - 1)
+		sp.PrevPageBodyURL = sp.BodyPageURL(sp.CurrentPage - 1)
 	}
 	if sp.HasNextPage {
-		sp.NextPageURL = sp.buildPageURL(sp.CurrentPage + 1)
+		sp.NextPageURL = sp.PageURL(sp.CurrentPage + 1)
+		sp.NextPageBodyURL = sp.BodyPageURL(sp.CurrentPage + 1)
 	}
 
 	// Page number buttons
-	sp.PageNumbers = buildPageNumbers(sp.CurrentPage, sp.TotalPages, sp.buildPageURL)
+	sp.PageNumbers = buildPageNumbers(sp.CurrentPage, sp.TotalPages, sp.PageURL)
+	for i := range sp.PageNumbers {
+		if !sp.PageNumbers[i].Ellipsis {
+			sp.PageNumbers[i].BodyURL = sp.BodyPageURL(sp.PageNumbers[i].Number)
+		}
+	}
 }
 
 // buildCursorDisplay computes cursor-mode display fields
 func (sp *ServerPagination) buildCursorDisplay() {
 	if sp.HasPrevPage && sp.PrevCursor != "" {
-		sp.PrevCursorURL = sp.buildCursorURL(sp.PrevCursor, "prev")
+		sp.PrevCursorURL = sp.CursorURL(sp.PrevCursor, "prev")
+		sp.PrevCursorBodyURL = sp.BodyCursorURL(sp.PrevCursor, "prev")
 	}
 	if sp.HasNextPage && sp.NextCursor != "" {
-		sp.NextCursorURL = sp.buildCursorURL(sp.NextCursor, "next")
+		sp.NextCursorURL = sp.CursorURL(sp.NextCursor, "next")
+		sp.NextCursorBodyURL = sp.BodyCursorURL(sp.NextCursor, "next")
 	}
 }
 
-// buildCursorURL constructs the HTMX URL for cursor navigation
-func (sp *ServerPagination) buildCursorURL(cursor, direction string) string {
-	url := sp.PaginationURL + "?cursor=" + cursor + "&curdir=" + direction + "&size=" + itoa(sp.PageSize)
-	if sp.SearchQuery != "" {
-		url += "&search=" + sp.SearchQuery
+// URL returns PaginationURL with Params added (the table's data-pagination-url)
+func (sp *ServerPagination) URL() string {
+	return sp.buildURL(sp.PaginationURL, nil)
+}
+
+// BodyURL returns PaginationBodyURL with Params added ("" without PaginationBodyURL)
+func (sp *ServerPagination) BodyURL() string {
+	if sp.PaginationBodyURL == "" {
+		return ""
 	}
-	if sp.SortColumn != "" {
-		url += "&sort=" + sp.SortColumn
-		dir := sp.SortDirection
-		if dir == "" {
-			dir = "asc"
-		}
-		url += "&dir=" + dir
+	return sp.buildURL(sp.PaginationBodyURL, nil)
+}
+
+// PageURL returns the HTMX URL for a specific page (offset mode)
+func (sp *ServerPagination) PageURL(page int) string {
+	return sp.buildURL(sp.PaginationURL, sp.pageParams(page))
+}
+
+// BodyPageURL returns the body-only URL for a specific page ("" without PaginationBodyURL)
+func (sp *ServerPagination) BodyPageURL(page int) string {
+	if sp.PaginationBodyURL == "" {
+		return ""
 	}
-	if sp.FiltersJSON != "" {
-		url += "&filters=" + sp.FiltersJSON
+	return sp.buildURL(sp.PaginationBodyURL, sp.pageParams(page))
+}
+
+// CursorURL returns the HTMX URL for cursor navigation ("next" or "prev")
+func (sp *ServerPagination) CursorURL(cursor, direction string) string {
+	return sp.buildURL(sp.PaginationURL, sp.cursorParams(cursor, direction))
+}
+
+// BodyCursorURL returns the body-only URL for cursor navigation ("" without PaginationBodyURL)
+func (sp *ServerPagination) BodyCursorURL(cursor, direction string) string {
+	if sp.PaginationBodyURL == "" {
+		return ""
 	}
-	return url
+	return sp.buildURL(sp.PaginationBodyURL, sp.cursorParams(cursor, direction))
+}
+
+// pageParams returns the params of a page request
+func (sp *ServerPagination) pageParams(page int) url.Values {
+	params := sp.stateParams()
+	params.Set("page", itoa(page))
+	return params
+}
+
+// cursorParams returns the params of a cursor request
+func (sp *ServerPagination) cursorParams(cursor, direction string) url.Values {
+	params := sp.stateParams()
+	params.Set("cursor", cursor)
+	params.Set("curdir", direction)
+	return params
 }
 
-// buildPageURL constructs the HTMX URL for a specific page
-func (sp *ServerPagination) buildPageURL(page int) string {
-	url := sp.PaginationURL + "?page=" + itoa(page) + "&size=" + itoa(sp.PageSize)
+// stateParams returns the size, search, sort and filter params shared by every request
+func (sp *ServerPagination) stateParams() url.Values {
+	params := url.Values{}
+	params.Set("size", itoa(sp.PageSize))
 	if sp.SearchQuery != "" {
-		url += "&search=" + sp.SearchQuery
+		params.Set("search", sp.SearchQuery)
 	}
 	if sp.SortColumn != "" {
-		url += "&sort=" + sp.SortColumn
+		params.Set("sort", sp.SortColumn)
 		dir := sp.SortDirection
 		if dir == "" {
 			dir = "asc"
 		}
-		url += "&dir=" + dir
+		params.Set("dir", dir)
 	}
 	if sp.FiltersJSON != "" {
-		url += "&filters=" + sp.FiltersJSON
+		params.Set("filters", sp.FiltersJSON)
 	}
-	return url
+	return params
+}
+
+// buildURL adds Params and then params to base, keeping base's own query params
+// (a param set in both is replaced) and fragment
+func (sp *ServerPagination) buildURL(base string, params url.Values) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		u = &url.URL{Path: base}
+	}
+	query := u.Query()
+	for key, values := range sp.Params {
+		query[key] = values
+	}
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
 }
 
 // buildPageNumbers generates the slice of page buttons with smart windowing
@@ -427,6 +516,18 @@ func buildPageNumbers(current, total int, urlBuilder func(int) string) []PageNum
 	return pages
 }
 
+// PageWindow returns the page numbers shown for the current page using the same
+// windowing as ServerPagination, with 0 marking an ellipsis (as the "pagination"
+// component expects)
+func PageWindow(current, total int) []int {
+	numbers := buildPageNumbers(current, total, func(int) string { return "" })
+	pages := make([]int, len(numbers))
+	for i, n := range numbers {
+		pages[i] = n.Number
+	}
+	return pages
+}
+
 // itoa is a shorthand for strconv.Itoa
 func itoa(n int) string {
 	return strconv.Itoa(n)
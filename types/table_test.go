@@ -0,0 +1,180 @@
+package types
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestServerPaginationStateParams(t *testing.T) {
+	tests := []struct {
+		name string
+		sp   ServerPagination
+		want string
+	}{
+		{
+			name: "size only",
+			sp:   ServerPagination{PageSize: 25},
+			want: "size=25",
+		},
+		{
+			name: "search with query characters",
+			sp:   ServerPagination{PageSize: 25, SearchQuery: "a&b"},
+			want: "search=a%26b&size=25",
+		},
+		{
+			name: "search with a fragment character",
+			sp:   ServerPagination{PageSize: 25, SearchQuery: "#1"},
+			want: "search=%231&size=25",
+		},
+		{
+			name: "sort without a direction defaults to asc",
+			sp:   ServerPagination{PageSize: 25, SortColumn: "name"},
+			want: "dir=asc&size=25&sort=name",
+		},
+		{
+			name: "direction without a sort column is dropped",
+			sp:   ServerPagination{PageSize: 25, SortDirection: "desc"},
+			want: "size=25",
+		},
+		{
+			name: "filters",
+			sp:   ServerPagination{PageSize: 25, FiltersJSON: "W3siYSI6MX0+/w=="},
+			want: "filters=W3siYSI6MX0%2B%2Fw%3D%3D&size=25",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sp.stateParams().Encode(); got != tt.want {
+				t.Errorf("stateParams = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerPaginationURLs(t *testing.T) {
+	tests := []struct {
+		name string
+		sp   ServerPagination
+		got  func(sp *ServerPagination) string
+		want string
+	}{
+		{
+			name: "page",
+			sp:   ServerPagination{PageSize: 10, SearchQuery: "a&b #1", PaginationURL: "/table"},
+			got:  func(sp *ServerPagination) string { return sp.PageURL(2) },
+			want: "/table?page=2&search=a%26b+%231&size=10",
+		},
+		{
+			name: "cursor with base64 characters",
+			sp:   ServerPagination{PageSize: 10, PaginationURL: "/table"},
+			got:  func(sp *ServerPagination) string { return sp.CursorURL("ab+c/d==", "prev") },
+			want: "/table?curdir=prev&cursor=ab%2Bc%2Fd%3D%3D&size=10",
+		},
+		{
+			name: "pagination URL query params and fragment kept",
+			sp:   ServerPagination{PageSize: 10, PaginationURL: "/table?tab=open#results"},
+			got:  func(sp *ServerPagination) string { return sp.PageURL(3) },
+			want: "/table?page=3&size=10&tab=open#results",
+		},
+		{
+			name: "pagination URL param replaced by a state param",
+			sp:   ServerPagination{PageSize: 10, PaginationURL: "/table?page=9&size=99"},
+			got:  func(sp *ServerPagination) string { return sp.PageURL(3) },
+			want: "/table?page=3&size=10",
+		},
+		{
+			name: "Params take precedence over pagination URL params",
+			sp:   ServerPagination{PageSize: 10, PaginationURL: "/table?tab=open&view=list", Params: url.Values{"tab": {"closed"}}},
+			got:  func(sp *ServerPagination) string { return sp.PageURL(1) },
+			want: "/table?page=1&size=10&tab=closed&view=list",
+		},
+		{
+			name: "state params take precedence over Params",
+			sp:   ServerPagination{PageSize: 10, PaginationURL: "/table", Params: url.Values{"size": {"99"}, "status": {"active"}}},
+			got:  func(sp *ServerPagination) string { return sp.PageURL(1) },
+			want: "/table?page=1&size=10&status=active",
+		},
+		{
+			name: "URL has Params but no state",
+			sp:   ServerPagination{PageSize: 10, SearchQuery: "x", PaginationURL: "/table?tab=open", Params: url.Values{"status": {"active"}}},
+			got:  func(sp *ServerPagination) string { return sp.URL() },
+			want: "/table?status=active&tab=open",
+		},
+		{
+			name: "body URL",
+			sp:   ServerPagination{PageSize: 10, PaginationURL: "/table", PaginationBodyURL: "/table/body#rows", Params: url.Values{"tab": {"open"}}},
+			got:  func(sp *ServerPagination) string { return sp.BodyURL() },
+			want: "/table/body?tab=open#rows",
+		},
+		{
+			name: "body page",
+			sp:   ServerPagination{PageSize: 10, PaginationURL: "/table", PaginationBodyURL: "/table/body"},
+			got:  func(sp *ServerPagination) string { return sp.BodyPageURL(2) },
+			want: "/table/body?page=2&size=10",
+		},
+		{
+			name: "body cursor",
+			sp:   ServerPagination{PageSize: 10, PaginationURL: "/table", PaginationBodyURL: "/table/body"},
+			got:  func(sp *ServerPagination) string { return sp.BodyCursorURL("a/b", "next") },
+			want: "/table/body?curdir=next&cursor=a%2Fb&size=10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got(&tt.sp); got != tt.want {
+				t.Errorf("URL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// A URL must give the server back exactly the state it was built from
+func TestServerPaginationURLRoundTrip(t *testing.T) {
+	sp := ServerPagination{PageSize: 10, SearchQuery: "a&b #1", FiltersJSON: "W3siYSI6MX0+/w==", SortColumn: "name", SortDirection: "desc", PaginationURL: "/table#results"}
+
+	u, err := url.Parse(sp.CursorURL("ab+c/d==", "next"))
+	if err != nil {
+		t.Fatalf("CursorURL is not a URL: %v", err)
+	}
+	query := u.Query()
+	for key, want := range map[string]string{"search": "a&b #1", "filters": "W3siYSI6MX0+/w==", "cursor": "ab+c/d==", "curdir": "next", "sort": "name", "dir": "desc"} {
+		if got := query.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	if u.Fragment != "results" {
+		t.Errorf("fragment = %q, want results", u.Fragment)
+	}
+}
+
+func TestServerPaginationBodyURLsWithoutBodyURL(t *testing.T) {
+	offset := &ServerPagination{Mode: "offset", PageSize: 10, CurrentPage: 2, TotalRows: 50, TotalPages: 5, PaginationURL: "/table"}
+	offset.BuildDisplay()
+	cursor := &ServerPagination{Mode: "cursor", PageSize: 10, HasPrevPage: true, HasNextPage: true, PrevCursor: "p", NextCursor: "n", PaginationURL: "/table"}
+	cursor.BuildDisplay()
+
+	got := map[string]string{
+		"BodyURL":           offset.BodyURL(),
+		"BodyPageURL":       offset.BodyPageURL(1),
+		"BodyCursorURL":     offset.BodyCursorURL("n", "next"),
+		"PrevPageBodyURL":   offset.PrevPageBodyURL,
+		"NextPageBodyURL":   offset.NextPageBodyURL,
+		"PrevCursorBodyURL": cursor.PrevCursorBodyURL,
+		"NextCursorBodyURL": cursor.NextCursorBodyURL,
+	}
+	for _, page := range offset.PageNumbers {
+		got["PageNumbers.BodyURL"] += page.BodyURL
+	}
+	for name, value := range got {
+		if value != "" {
+			t.Errorf("%s = %q, want empty without PaginationBodyURL", name, value)
+		}
+	}
+
+	// the full-table URLs are still built
+	if offset.NextPageURL == "" || cursor.NextCursorURL == "" {
+		t.Errorf("NextPageURL, NextCursorURL = %q, %q, want both set", offset.NextPageURL, cursor.NextCursorURL)
+	}
+}
@@ -0,0 +1,317 @@
+package ui
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/template/parse"
+)
+
+// ComponentSchema lists the dict keys a component accepts and which of them are required
+type ComponentSchema struct {
+	Keys     []string // every key the component reads
+	Required []string // keys that must be passed
+}
+
+// TemplateIssue is a problem found by ValidateTemplates
+type TemplateIssue struct {
+	Location string // template file, line and column (e.g., "list.html:12:5")
+	Template string // name of the template containing the call
+	Target   string // name of the called template
+	Message  string
+}
+
+func (i TemplateIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Location, i.Message)
+}
+
+var (
+	schemaMu         sync.RWMutex
+	componentSchemas = map[string]ComponentSchema{}
+)
+
+// RegisterComponentSchema declares the keys of a component's dict. props is a props
+// struct (or pointer to one) whose exported field names are the accepted keys;
+// required lists the keys callers must pass.
+//
+// Example:
+//
+//	ui.RegisterComponentSchema("client-card", ClientCardProps{}, "Name")
+func RegisterComponentSchema(name string, props any, required ...string) {
+	schema := ComponentSchema{Keys: propKeys(props), Required: required}
+	schemaMu.Lock()
+	componentSchemas[name] = schema
+	schemaMu.Unlock()
+}
+
+// GetComponentSchema returns the schema declared for a component
+func GetComponentSchema(name string) (ComponentSchema, bool) {
+	schemaMu.RLock()
+	defer schemaMu.RUnlock()
+	schema, ok := componentSchemas[name]
+	return schema, ok
+}
+
+// propKeys returns the exported field names of a props struct
+func propKeys(props any) []string {
+	t := reflect.TypeOf(props)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() {
+			keys = append(keys, field.Name)
+		}
+	}
+	return keys
+}
+
+// Schemas of the shared components, derived from their props structs (see props.go)
+func init() {
+	RegisterComponentSchema("alert", AlertProps{}, "Message")
+	RegisterComponentSchema("avatar", AvatarProps{})
+	RegisterComponentSchema("badge", BadgeProps{}, "Label")
+	RegisterComponentSchema("status-badge", BadgeProps{}, "Label")
+	RegisterComponentSchema("type-badge", BadgeProps{}, "Label")
+	RegisterComponentSchema("count-badge", BadgeProps{}, "Count")
+	RegisterComponentSchema("tag-badge", BadgeProps{}, "Label")
+	RegisterComponentSchema("button", ButtonProps{})
+	RegisterComponentSchema("button-group", ButtonGroupProps{})
+	RegisterComponentSchema("card", CardProps{})
+	RegisterComponentSchema("card-header", CardProps{})
+	RegisterComponentSchema("quick-link-card", QuickLinkCardProps{}, "Title", "Href")
+	RegisterComponentSchema("article-card", ArticleCardProps{}, "Title")
+	RegisterComponentSchema("chip", ChipProps{}, "ID")
+	RegisterComponentSchema("chip-group", ChipGroupProps{}, "Chips")
+	RegisterComponentSchema("dialog-confirm", DialogConfirmProps{}, "Title", "Message")
+	RegisterComponentSchema("dropdown-filter", DropdownProps{}, "ID", "Options")
+	RegisterComponentSchema("dropdown-action", DropdownProps{}, "ID", "Items")
+	RegisterComponentSchema("dropdown-icon", DropdownProps{}, "ID", "Items")
+	RegisterComponentSchema("empty-state", EmptyStateProps{}, "Title")
+	RegisterComponentSchema("form-group", FormGroupProps{}, "Name")
+	RegisterComponentSchema("form-row", FormRowProps{})
+	RegisterComponentSchema("form-section", FormSectionProps{})
+	RegisterComponentSchema("multi-select", MultiSelectProps{}, "ID", "Name")
+	RegisterComponentSchema("notification-card", NotificationCardProps{}, "ID")
+	RegisterComponentSchema("sheet", SheetProps{})
+	RegisterComponentSchema("sheet-form", SheetFormProps{}, "CommonLabels")
+	RegisterComponentSchema("sheet-form-footer", SheetFormFooterProps{}, "CommonLabels")
+	RegisterComponentSchema("sidebar", SidebarProps{}, "Nav")
+	RegisterComponentSchema("sidebar-toggle", SidebarToggleProps{})
+	RegisterComponentSchema("skeleton", SkeletonProps{})
+	RegisterComponentSchema("skeleton-table", SkeletonProps{})
+	RegisterComponentSchema("spinner", SpinnerProps{})
+	RegisterComponentSchema("spinner-indicator", SpinnerProps{})
+	RegisterComponentSchema("stat-card", StatCardProps{}, "Value", "Label")
+	RegisterComponentSchema("stat-mini", StatCardProps{}, "Value", "Label")
+	RegisterComponentSchema("tabs", TabsProps{}, "Items")
+	RegisterComponentSchema("toast", ToastProps{}, "Message")
+	RegisterComponentSchema("toggle", ToggleProps{}, "Name")
+	RegisterComponentSchema("toggle-item", ToggleProps{}, "Name", "Label")
+}
+
+// WithStrictValidation makes Init fail when ValidateTemplates reports any issue.
+// By default issues are only logged.
+func (r *HTMLRenderer) WithStrictValidation() *HTMLRenderer {
+	r.strictValidation = true
+	return r
+}
+
+// Validate checks the renderer's templates (shared set and pages), see ValidateTemplates
+func (r *HTMLRenderer) Validate() ([]TemplateIssue, error) {
+	if err := r.Init(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	set := &templateSet{templates: r.templates, pages: r.pages}
+	r.mu.RUnlock()
+	return set.validate(), nil
+}
+
+// validate checks the shared set and every page set, reporting each issue once
+func (s *templateSet) validate() []TemplateIssue {
+	issues := ValidateTemplates(s.templates)
+
+	seen := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		seen[issue.String()] = true
+	}
+
+	names := make([]string, 0, len(s.pages))
+	for name := range s.pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, issue := range ValidateTemplates(s.pages[name]) {
+			if !seen[issue.String()] {
+				seen[issue.String()] = true
+				issues = append(issues, issue)
+			}
+		}
+	}
+	return issues
+}
+
+// checkTemplates validates a freshly parsed set: issues are logged, and in strict
+// mode returned as an error
+func (r *HTMLRenderer) checkTemplates(set *templateSet) error {
+	issues := set.validate()
+	if len(issues) == 0 {
+		return nil
+	}
+
+	for _, issue := range issues {
+		log.Printf("Template validation: %s", issue)
+	}
+	if r.strictValidation {
+		return fmt.Errorf("template validation failed with %d issues (first: %s)", len(issues), issues[0])
+	}
+	return nil
+}
+
+// ValidateTemplates walks the parse trees of every template in t and reports
+//   - {{template "x"}} calls whose target is not defined
+//   - dict keys a component's schema does not know (usually a typo)
+//   - required schema keys missing from a dict
+//
+// Only literal dict calls ({{template "x" dict "Key" value}}) are checked against
+// schemas; other arguments (structs, "." ...) are left to the props types.
+// Must be called before the templates are executed.
+func ValidateTemplates(t *template.Template) []TemplateIssue {
+	if t == nil {
+		return nil
+	}
+
+	templates := t.Templates()
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name() < templates[j].Name()
+	})
+
+	var issues []TemplateIssue
+	for _, tmpl := range templates {
+		if tmpl.Tree == nil || tmpl.Tree.Root == nil {
+			continue
+		}
+		v := &templateValidator{set: t, tree: tmpl.Tree, name: tmpl.Name()}
+		v.walk(tmpl.Tree.Root)
+		issues = append(issues, v.issues...)
+	}
+	return issues
+}
+
+// templateValidator collects the issues of a single parse tree
+type templateValidator struct {
+	set    *template.Template
+	tree   *parse.Tree
+	name   string
+	issues []TemplateIssue
+}
+
+// walk visits every node that can contain a {{template}} call
+func (v *templateValidator) walk(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			v.walk(child)
+		}
+	case *parse.IfNode:
+		v.walk(n.List)
+		v.walk(n.ElseList)
+	case *parse.RangeNode:
+		v.walk(n.List)
+		v.walk(n.ElseList)
+	case *parse.WithNode:
+		v.walk(n.List)
+		v.walk(n.ElseList)
+	case *parse.TemplateNode:
+		v.checkCall(n)
+	}
+}
+
+// checkCall validates a single {{template "x" ...}} call
+func (v *templateValidator) checkCall(n *parse.TemplateNode) {
+	if v.set.Lookup(n.Name) == nil {
+		v.report(n, n.Name, fmt.Sprintf("template %q is not defined", n.Name))
+		return
+	}
+
+	schema, ok := GetComponentSchema(n.Name)
+	if !ok {
+		return
+	}
+	keys, ok := dictKeys(n.Pipe)
+	if !ok {
+		return
+	}
+
+	known := make(map[string]bool, len(schema.Keys))
+	for _, key := range schema.Keys {
+		known[key] = true
+	}
+	passed := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		passed[key] = true
+		if !known[key] {
+			v.report(n, n.Name, fmt.Sprintf("unknown key %q for %q (expected one of %s)",
+				key, n.Name, strings.Join(schema.Keys, ", ")))
+		}
+	}
+	for _, key := range schema.Required {
+		if !passed[key] {
+			v.report(n, n.Name, fmt.Sprintf("missing required key %q for %q", key, n.Name))
+		}
+	}
+}
+
+func (v *templateValidator) report(n parse.Node, target, message string) {
+	location, _ := v.tree.ErrorContext(n)
+	v.issues = append(v.issues, TemplateIssue{
+		Location: location,
+		Template: v.name,
+		Target:   target,
+		Message:  message,
+	})
+}
+
+// dictKeys returns the keys of a literal dict call passed as a template argument,
+// e.g. `dict "A" 1 "B" .X` or `(dict "A" 1)`. ok is false for any other argument
+// or when a key is not a string literal.
+func dictKeys(pipe *parse.PipeNode) (keys []string, ok bool) {
+	if pipe == nil || len(pipe.Cmds) != 1 {
+		return nil, false
+	}
+	args := pipe.Cmds[0].Args
+	if len(args) == 1 {
+		if inner, isPipe := args[0].(*parse.PipeNode); isPipe {
+			return dictKeys(inner)
+		}
+	}
+	if len(args) == 0 {
+		return nil, false
+	}
+	if ident, isIdent := args[0].(*parse.IdentifierNode); !isIdent || ident.Ident != "dict" {
+		return nil, false
+	}
+
+	for i := 1; i < len(args); i += 2 {
+		key, isString := args[i].(*parse.StringNode)
+		if !isString {
+			return nil, false
+		}
+		keys = append(keys, key.Text)
+	}
+	return keys, true
+}
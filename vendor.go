@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"encoding/json"
+	"log"
+	"sync/atomic"
+)
+
+// HTMXVersion selects the vendored HTMX major version the shared partials load
+// and the markup they emit for it
+type HTMXVersion int
+
+const (
+	HTMX1 HTMXVersion = 1 // htmx 1.x (default)
+	HTMX2 HTMXVersion = 2 // htmx 2.x
+)
+
+// Vendored HTMX releases (assets/vendor/htmx, fetched by scripts/vendor-htmx.sh).
+// Bump these together with the script and re-run it.
+const (
+	HTMX1Release = "1.9.12"
+	HTMX2Release = "2.0.4"
+)
+
+// htmxAssets maps each version to its logical asset name (see AssetURL)
+var htmxAssets = map[HTMXVersion]string{
+	HTMX1: "components/vendor/htmx-" + HTMX1Release + ".min.js",
+	HTMX2: "components/vendor/htmx-" + HTMX2Release + ".min.js",
+}
+
+// htmxConfigs is the htmx-config each version is loaded with, so the components
+// behave the same under both: 1.x parses <template> fragments like 2.x (table rows
+// in OOB swaps), and 2.x keeps sending DELETE parameters in the body like 1.x.
+// Neither injects its indicator <style> (blocked by CSP; loading-indicator.css has the rules).
+var htmxConfigs = map[HTMXVersion]map[string]any{
+	HTMX1: {"useTemplateFragments": true, "includeIndicatorStyles": false},
+	HTMX2: {"methodsThatUseUrlParams": []string{"get"}, "includeIndicatorStyles": false},
+}
+
+// htmxVersion is the version set via SetHTMXVersion
+var htmxVersion atomic.Int32
+
+func init() {
+	htmxVersion.Store(int32(HTMX1))
+}
+
+// SetHTMXVersion selects the HTMX major version loaded by the scripts partial (HTMX1 or HTMX2).
+// Defaults to HTMX1. Apps that write their own hx-* markup should pick the version it targets.
+//
+// Example:
+//
+//	ui.SetHTMXVersion(ui.HTMX2)
+func SetHTMXVersion(v HTMXVersion) {
+	if _, ok := htmxAssets[v]; !ok {
+		log.Printf("Warning: Unsupported HTMX version %d, keeping %d", v, GetHTMXVersion())
+		return
+	}
+	htmxVersion.Store(int32(v))
+}
+
+// GetHTMXVersion returns the HTMX major version set via SetHTMXVersion
+func GetHTMXVersion() HTMXVersion {
+	return HTMXVersion(htmxVersion.Load())
+}
+
+// HTMXAsset returns the logical asset name of the selected HTMX build
+// (e.g., "components/vendor/htmx-1.9.12.min.js")
+func HTMXAsset() string {
+	return htmxAssets[GetHTMXVersion()]
+}
+
+// htmxInlineScriptNonce is set via SetHTMXInlineScriptNonce
+var htmxInlineScriptNonce atomic.Bool
+
+// SetHTMXInlineScriptNonce makes HTMXConfig pass the page's CSP nonce to HTMX as
+// inlineScriptNonce (off by default). HTMX then stamps that nonce on every <script>
+// in swapped-in responses, so any markup injected into a fragment (e.g., through
+// unescaped user content) runs as trusted script, which defeats the nonce-based CSP.
+// Only enable it if every fragment the app swaps in is fully trusted; the components
+// themselves need no inline scripts in fragments (they use delegated data-* handlers).
+func SetHTMXInlineScriptNonce(enabled bool) {
+	htmxInlineScriptNonce.Store(enabled)
+}
+
+// HTMXConfig returns the JSON htmx-config for the selected version
+// (rendered as <meta name="htmx-config"> by the "htmx-config" partial).
+// nonce is only included, as inlineScriptNonce, when enabled with SetHTMXInlineScriptNonce.
+func HTMXConfig(nonce string) string {
+	config := make(map[string]any, len(htmxConfigs[GetHTMXVersion()])+1)
+	for key, value := range htmxConfigs[GetHTMXVersion()] {
+		config[key] = value
+	}
+	if nonce != "" && htmxInlineScriptNonce.Load() {
+		config["inlineScriptNonce"] = nonce
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
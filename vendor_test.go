@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHTMXConfigInlineScriptNonce(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		want    any
+	}{
+		{name: "off by default", enabled: false, want: nil},
+		{name: "opt-in", enabled: true, want: "abc123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetHTMXInlineScriptNonce(tt.enabled)
+			defer SetHTMXInlineScriptNonce(false)
+
+			var config map[string]any
+			if err := json.Unmarshal([]byte(HTMXConfig("abc123")), &config); err != nil {
+				t.Fatalf("HTMXConfig is not JSON: %v", err)
+			}
+			if got := config["inlineScriptNonce"]; got != tt.want {
+				t.Errorf("inlineScriptNonce = %v, want %v", got, tt.want)
+			}
+			if config["includeIndicatorStyles"] != false {
+				t.Errorf("config %v lost the version defaults", config)
+			}
+		})
+	}
+}